@@ -18,6 +18,8 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -46,10 +48,689 @@ type AppSpec struct {
 	// +kubebuilder:validation:Maximum=65535
 	Port int32 `json:"port"`
 
+	// Resources of the app container
+	// +optional
+	Resources *ResourcesSpec `json:"resources,omitempty"`
+
+	// SecurityContext of the app container
+	// +optional
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Paused stops the operator from changing the objects of the App, e.g. to
+	// debug them by hand. The status is still updated.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Maintenance routes the traffic of the Service to a maintenance page
+	// +optional
+	Maintenance *MaintenanceSpec `json:"maintenance,omitempty"`
+
 	// Optional environment variables
 	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// SecretsFrom copies secrets of external stores into a Secret owned by the App,
+	// exposed to the app container as environment variables
+	// +optional
+	SecretsFrom []SecretSource `json:"secretsFrom,omitempty"`
+
+	// InitContainers run to completion, in order, before the app container starts
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Sidecars run alongside the app container for the lifetime of the pod.
+	// They are native sidecars, started after the init containers.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Volumes of the pod, mounted by the app container, the init containers and the sidecars
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Volumes []Volume `json:"volumes,omitempty"`
+
+	// VolumeMounts of the app container. They must reference spec.volumes.
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Service configures the Service exposing the app.
+	// When omitted, a ClusterIP Service exposing spec.port over TCP is created.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+
+	// Ingress exposes the app outside of the cluster
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+
+	// TLS requests a certificate from cert-manager, mounted into the app
+	// container and served by the Ingress
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// Monitoring makes the operator create a prometheus-operator
+	// ServiceMonitor or PodMonitor scraping the app.
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Telemetry opts the app in to OpenTelemetry auto-instrumentation
+	// through the OpenTelemetry Operator.
+	// +optional
+	Telemetry *TelemetrySpec `json:"telemetry,omitempty"`
+
+	// HealthChecks are performed by the operator against the Service of the App,
+	// reporting whether the app is reachable through it
+	// +optional
+	HealthChecks *HealthChecksSpec `json:"healthChecks,omitempty"`
+
+	// ServiceAccount runs the pods as a ServiceAccount owned by the App instead
+	// of the default ServiceAccount of the namespace
+	// +optional
+	ServiceAccount *ServiceAccountSpec `json:"serviceAccount,omitempty"`
+
+	// Inference runs spec.image as an Ollama model server: the model is pulled
+	// before the app container starts, which becomes ready once the model is loaded
+	// +optional
+	Inference *InferenceSpec `json:"inference,omitempty"`
+
+	// Mesh adds the app to a service mesh: the sidecar is injected into the pods
+	// and, with Istio, the traffic policy is applied to the Service
+	// +optional
+	Mesh *MeshSpec `json:"mesh,omitempty"`
+}
+
+// ServiceType is the type of Service created for an App.
+// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer;Headless
+type ServiceType string
+
+const (
+	ServiceTypeClusterIP    ServiceType = "ClusterIP"
+	ServiceTypeNodePort     ServiceType = "NodePort"
+	ServiceTypeLoadBalancer ServiceType = "LoadBalancer"
+	// ServiceTypeHeadless is a ClusterIP Service without a cluster IP.
+	ServiceTypeHeadless ServiceType = "Headless"
+)
+
+// ServiceSpec defines how the app is exposed inside the cluster
+type ServiceSpec struct {
+	// Type of the Service. Switching to or from Headless requires the
+	// Service to be recreated because the cluster IP is immutable.
+	// +kubebuilder:default=ClusterIP
+	// +optional
+	Type ServiceType `json:"type,omitempty"`
+
+	// Ports exposed by the Service. Defaults to a single TCP port named
+	// "http" that forwards spec.port.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Ports []ServicePort `json:"ports,omitempty"`
+
+	// SessionAffinity of the Service
+	// +kubebuilder:validation:Enum=None;ClientIP
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
+	// Annotations added to the Service, e.g. for cloud load balancers
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServicePort is a port exposed by the Service and the app container
+type ServicePort struct {
+	// Name of the port, must be unique within the Service. An IANA service
+	// name, with at least one letter and no consecutive hyphens, so that the
+	// probes and health checks can refer to it.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:XValidation:rule="self.matches('[a-z]') && !self.contains('--')",message="must contain at least one letter and no consecutive hyphens"
+	Name string `json:"name"`
+
+	// Port exposed by the Service
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// TargetPort on the app container. Defaults to port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	TargetPort int32 `json:"targetPort,omitempty"`
+
+	// Protocol of the port
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +kubebuilder:default=TCP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// AppProtocol hints the application protocol, e.g. http, h2c or grpc
+	// +optional
+	AppProtocol *string `json:"appProtocol,omitempty"`
+
+	// NodePort to use for NodePort and LoadBalancer Services.
+	// Allocated by the cluster when omitted.
+	// +kubebuilder:validation:Minimum=30000
+	// +kubebuilder:validation:Maximum=32767
+	// +optional
+	NodePort int32 `json:"nodePort,omitempty"`
+}
+
+// ResourcesSpec defines the compute resources of the app container
+type ResourcesSpec struct {
+	// Requests of the app container, e.g. cpu: 100m
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+
+	// Limits of the app container
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+
+	// AutoTune adjusts the requests of the app container to its observed usage.
+	// spec.resources.requests are the initial requests.
+	// +optional
+	AutoTune *AutoTuneSpec `json:"autoTune,omitempty"`
+}
+
+// AutoTuneSpec defines the policy of the automatic tuning of the requests
+type AutoTuneSpec struct {
+	// MinAllowed are the lowest requests the operator may set
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// MaxAllowed are the highest requests the operator may set
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+
+	// MinChangePercent is the smallest change of a request worth a rollout
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// +optional
+	MinChangePercent int32 `json:"minChangePercent,omitempty"`
+
+	// RolloutWindows restrict when tuned requests are rolled out. Any time when empty.
+	// +optional
+	RolloutWindows []RolloutWindow `json:"rolloutWindows,omitempty"`
+}
+
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// RolloutWindow is a daily time range, in UTC
+type RolloutWindow struct {
+	// Days of the week of the window. Every day when empty.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start of the window, e.g. 02:00
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End of the window, e.g. 05:00. Before Start for a window spanning midnight.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
+
+// MaintenanceSpec defines the backend serving the app during a maintenance
+type MaintenanceSpec struct {
+	// Enabled switches the Service to the maintenance backend
+	Enabled bool `json:"enabled"`
+
+	// Selector of the pods serving the maintenance page, in the namespace of the App.
+	// They must listen on the target ports of the Service.
+	// +kubebuilder:validation:MinProperties=1
+	Selector map[string]string `json:"selector"`
+}
+
+// Volume is a volume of the pod. Exactly one source must be set.
+type Volume struct {
+	// Name of the volume, referenced by the volume mounts
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ConfigMap mounted as files
+	// +optional
+	ConfigMap *corev1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+
+	// Secret mounted as files
+	// +optional
+	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
+
+	// EmptyDir is a scratch directory sharing the lifetime of the pod
+	// +optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// PersistentVolumeClaim of the namespace of the App
+	// +optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// Projected merges several sources in one directory
+	// +optional
+	Projected *corev1.ProjectedVolumeSource `json:"projected,omitempty"`
+}
+
+// SecretProvider is the store a secret is read from
+// +kubebuilder:validation:Enum=Vault;Secret
+type SecretProvider string
+
+const (
+	// SecretProviderVault reads a document of the file-backed vault mounted in the operator
+	SecretProviderVault SecretProvider = "Vault"
+	// SecretProviderSecret reads a Secret shared from another namespace
+	SecretProviderSecret SecretProvider = "Secret"
+)
+
+// SecretSource selects keys of a secret held by an external store
+type SecretSource struct {
+	// Provider holding the secret
+	Provider SecretProvider `json:"provider"`
+
+	// Path of the secret: the path of the document for Vault,
+	// <namespace>/<name> for Secret
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// Keys copied from the secret. Every key is copied when empty.
+	// +optional
+	Keys []SecretKey `json:"keys,omitempty"`
+}
+
+// SecretKey maps a key of an external secret to an environment variable
+type SecretKey struct {
+	// Key in the external secret
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Env is the name of the environment variable. Defaults to the key.
+	// +optional
+	Env string `json:"env,omitempty"`
+}
+
+// IngressSpec defines the Ingress routing external traffic to the app
+type IngressSpec struct {
+	// Host served by the Ingress, e.g. web.example.com
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	Host string `json:"host"`
+
+	// ClassName of the Ingress controller, e.g. traefik.
+	// Defaults to the default IngressClass of the cluster.
+	// +optional
+	ClassName *string `json:"className,omitempty"`
+
+	// Path prefix routed to the app
+	// +kubebuilder:default=/
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Port is the name of the Service port receiving the traffic.
+	// Defaults to the first Service port.
+	// +optional
+	Port string `json:"port,omitempty"`
+
+	// Annotations added to the Ingress, e.g. for the Ingress controller
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServiceAccountSpec defines the ServiceAccount of the pods and its permissions
+type ServiceAccountSpec struct {
+	// Annotations added to the ServiceAccount, e.g. the workload identity of the
+	// cloud provider: eks.amazonaws.com/role-arn or iam.gke.io/gcp-service-account
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Rules of the Role bound to the ServiceAccount, in the namespace of the App.
+	// No Role is created when empty.
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+
+	// AutomountToken mounts the API token of the ServiceAccount into the pods
+	// +kubebuilder:default=true
+	// +optional
+	AutomountToken *bool `json:"automountToken,omitempty"`
+}
+
+// InferenceProfile sizes the app container for a class of models
+// +kubebuilder:validation:Enum=Small;Medium;Large;GPU
+type InferenceProfile string
+
+const (
+	// InferenceProfileSmall fits models up to about 3B parameters on CPU
+	InferenceProfileSmall InferenceProfile = "Small"
+	// InferenceProfileMedium fits models up to about 8B parameters on CPU
+	InferenceProfileMedium InferenceProfile = "Medium"
+	// InferenceProfileLarge fits models up to about 14B parameters on CPU
+	InferenceProfileLarge InferenceProfile = "Large"
+	// InferenceProfileGPU schedules the pods on a node with an NVIDIA GPU
+	InferenceProfileGPU InferenceProfile = "GPU"
+)
+
+// InferenceSpec defines the model served by the app
+type InferenceSpec struct {
+	// Model pulled and served, e.g. llama3.2 or qwen2.5:0.5b
+	// +kubebuilder:validation:MinLength=1
+	Model string `json:"model"`
+
+	// Profile sets the requests and limits of the app container, unless spec.resources is set
+	// +kubebuilder:default=Small
+	// +optional
+	Profile InferenceProfile `json:"profile,omitempty"`
+
+	// Cache keeps the pulled models in a PersistentVolumeClaim owned by the App.
+	// The models are pulled again by every pod without it.
+	// +optional
+	Cache *ModelCacheSpec `json:"cache,omitempty"`
+}
+
+// ModelCacheSpec defines the PersistentVolumeClaim holding the models
+type ModelCacheSpec struct {
+	// Size of the volume
+	// +kubebuilder:default="20Gi"
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName of the volume. Defaults to the default StorageClass of the cluster.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessMode of the volume. With ReadWriteOnce, the pods are recreated
+	// rather than rolled out so that the new pod can attach the volume.
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany
+	// +kubebuilder:default=ReadWriteOnce
+	// +optional
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// Resources returns the requests and limits of the profile
+func (p InferenceProfile) Resources() corev1.ResourceRequirements {
+	cpu, memory, limit := "1", "4Gi", "8Gi"
+	switch p {
+	case InferenceProfileMedium, InferenceProfileGPU:
+		cpu, memory, limit = "2", "8Gi", "16Gi"
+	case InferenceProfileLarge:
+		cpu, memory, limit = "4", "16Gi", "32Gi"
+	}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(limit),
+		},
+	}
+	if p == InferenceProfileGPU {
+		resources.Limits["nvidia.com/gpu"] = resource.MustParse("1")
+	}
+	return resources
+}
+
+// TLSSpec defines the certificate issued by cert-manager for the app
+type TLSSpec struct {
+	// DNSNames of the certificate. Defaults to spec.ingress.host.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IssuerRef is the cert-manager Issuer or ClusterIssuer signing the certificate
+	IssuerRef IssuerReference `json:"issuerRef"`
+
+	// MountPath of the directory holding tls.crt, tls.key and ca.crt in the app container
+	// +kubebuilder:default=/etc/tls
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// IssuerReference references a cert-manager issuer
+type IssuerReference struct {
+	// Name of the issuer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer, Issuer in the namespace of the App or ClusterIssuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer, cert-manager.io or the group of an external issuer
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
 }
 
+// MonitorKind is the prometheus-operator resource scraping the app
+// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+type MonitorKind string
+
+const (
+	MonitorKindServiceMonitor MonitorKind = "ServiceMonitor"
+	MonitorKindPodMonitor     MonitorKind = "PodMonitor"
+)
+
+// MonitoringSpec defines how Prometheus scrapes the app
+type MonitoringSpec struct {
+	// Kind of monitor to create. A ServiceMonitor scrapes through the
+	// Service, a PodMonitor scrapes every pod directly.
+	// +kubebuilder:default=ServiceMonitor
+	// +optional
+	Kind MonitorKind `json:"kind,omitempty"`
+
+	// Port is the name of the port serving metrics
+	// +kubebuilder:default=http
+	// +optional
+	Port string `json:"port,omitempty"`
+
+	// Path of the metrics endpoint
+	// +kubebuilder:default=/metrics
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Interval between scrapes, e.g. 30s. Defaults to the Prometheus global interval.
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Labels added to the monitor on top of the operator defaults,
+	// e.g. to match the serviceMonitorSelector of a Prometheus
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// TelemetrySpec defines how the app is auto-instrumented
+type TelemetrySpec struct {
+	// Language of the app, selects the instrumentation injected by the
+	// OpenTelemetry Operator
+	// +kubebuilder:validation:Enum=java;nodejs;python;dotnet;go
+	Language string `json:"language"`
+
+	// Instrumentation resource to use, as "name" or "namespace/name".
+	// Defaults to the only Instrumentation of the App namespace.
+	// +optional
+	Instrumentation string `json:"instrumentation,omitempty"`
+
+	// Endpoint of the OTLP collector, e.g.
+	// http://collector-collector.otel.svc.cluster.local:4318.
+	// Defaults to the exporter of the Instrumentation resource.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// SamplingRatio of traces between 0 and 1, e.g. "0.25".
+	// Defaults to the sampler of the Instrumentation resource.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	SamplingRatio string `json:"samplingRatio,omitempty"`
+
+	// ExecutablePath of the app binary, required by Go auto-instrumentation
+	// +optional
+	ExecutablePath string `json:"executablePath,omitempty"`
+}
+
+// MeshProvider is the service mesh an App is added to
+// +kubebuilder:validation:Enum=Istio;Linkerd
+type MeshProvider string
+
+const (
+	MeshProviderIstio   MeshProvider = "Istio"
+	MeshProviderLinkerd MeshProvider = "Linkerd"
+)
+
+// MTLSMode is the mode of the connections of the clients to an App
+// +kubebuilder:validation:Enum=Strict;Disable
+type MTLSMode string
+
+const (
+	// MTLSModeStrict requires mutual TLS with the certificates of the mesh:
+	// the clients send it and the pods refuse plain text
+	MTLSModeStrict MTLSMode = "Strict"
+	// MTLSModeDisable connects in plain text, to and from the pods
+	MTLSModeDisable MTLSMode = "Disable"
+)
+
+// MeshSpec defines how the app joins the service mesh
+type MeshSpec struct {
+	// Provider of the mesh
+	// +kubebuilder:default=Istio
+	// +optional
+	Provider MeshProvider `json:"provider,omitempty"`
+
+	// Inject requests the injection of the mesh sidecar into the pods.
+	// False opts the pods out of a namespace-wide injection.
+	// +kubebuilder:default=true
+	// +optional
+	Inject *bool `json:"inject,omitempty"`
+
+	// Timeout of the HTTP requests to the app, e.g. 10s. Istio only.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Retries of the failed HTTP requests to the app. Istio only.
+	// +optional
+	Retries *MeshRetries `json:"retries,omitempty"`
+
+	// MTLS is the mode of the connections to the app, applied to the clients
+	// with a DestinationRule and to the pods with a PeerAuthentication. Istio only.
+	// Defaults to the mesh-wide setting.
+	// +optional
+	MTLS MTLSMode `json:"mtls,omitempty"`
+}
+
+// MeshRetries defines how failed requests are retried by the mesh
+type MeshRetries struct {
+	// Attempts is the number of retries of a request
+	// +kubebuilder:validation:Minimum=0
+	Attempts int32 `json:"attempts"`
+
+	// PerTryTimeout is the timeout of each attempt, e.g. 2s
+	// +optional
+	PerTryTimeout *metav1.Duration `json:"perTryTimeout,omitempty"`
+
+	// RetryOn lists the failures retried, e.g. 5xx,connect-failure.
+	// Defaults to the Istio policy.
+	// +optional
+	RetryOn string `json:"retryOn,omitempty"`
+}
+
+// HasTrafficPolicy reports whether the timeout, retries or mTLS mode are set
+func (m *MeshSpec) HasTrafficPolicy() bool {
+	return m.Timeout != nil || m.Retries != nil || m.MTLS != ""
+}
+
+// HealthChecksSpec configures the checks performed by the operator
+type HealthChecksSpec struct {
+	// Checks performed on every reconciliation. All of them must pass.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Checks []HealthCheck `json:"checks"`
+
+	// Timeout of each check
+	// +kubebuilder:default="5s"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// ReadinessGate adds the HealthCheckReadinessGate condition to the readiness
+	// gates of the pods: a pod becomes ready once the checks pass against it too
+	// +optional
+	ReadinessGate bool `json:"readinessGate,omitempty"`
+}
+
+// HealthCheck is a check of one port of the Service. Exactly one of http, tcp or grpc is set.
+type HealthCheck struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Port of the Service checked, by name. Defaults to the first port.
+	// +optional
+	Port string `json:"port,omitempty"`
+
+	// HTTP passes on a status code from 200 to 399
+	// +optional
+	HTTP *HTTPHealthCheck `json:"http,omitempty"`
+
+	// TCP passes when a connection is established
+	// +optional
+	TCP *TCPHealthCheck `json:"tcp,omitempty"`
+
+	// GRPC passes when the grpc.health.v1 service reports SERVING
+	// +optional
+	GRPC *GRPCHealthCheck `json:"grpc,omitempty"`
+}
+
+// HTTPHealthCheck sends a GET request
+type HTTPHealthCheck struct {
+	// Path of the request
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// TCPHealthCheck opens a TCP connection
+type TCPHealthCheck struct{}
+
+// GRPCHealthCheck calls the standard gRPC health service
+type GRPCHealthCheck struct {
+	// Service name sent in the HealthCheckRequest. Empty checks the whole server.
+	// +optional
+	Service string `json:"service,omitempty"`
+}
+
+// HealthCheckReadinessGate is the pod condition set by the operator with the
+// result of spec.healthChecks against the pod, when healthChecks.readinessGate is set
+const HealthCheckReadinessGate corev1.PodConditionType = "apps.test.local/externally-healthy"
+
+// Condition types reported in AppStatus.Conditions
+const (
+	// ConditionReady is True when the Deployment rolled out the current spec
+	// and all its pods are ready
+	ConditionReady = "Ready"
+	// ConditionReconciling is True while the Deployment rolls out the current spec
+	ConditionReconciling = "Reconciling"
+	// ConditionStalled is True when the App cannot progress without a change of
+	// its spec: it is invalid, violates the policy or missed its progress deadline
+	ConditionStalled = "Stalled"
+	// ConditionMonitoring reports whether the ServiceMonitor or PodMonitor is in place
+	ConditionMonitoring = "Monitoring"
+	// ConditionSecretsSynced reports whether spec.secretsFrom was read from the stores
+	ConditionSecretsSynced = "SecretsSynced"
+	// ConditionPaused reports whether spec.paused stops the reconciliation
+	ConditionPaused = "Paused"
+	// ConditionMaintenance reports whether the Service routes to the maintenance backend
+	ConditionMaintenance = "Maintenance"
+	// ConditionSpecValid reports whether the spec passed the checks the CRD schema cannot express
+	ConditionSpecValid = "SpecValid"
+	// ConditionRightSizing is True when the requests of the app container are
+	// well above its usage, with the recommended requests in the message
+	ConditionRightSizing = "RightSizing"
+	// ConditionExternallyHealthy reports whether spec.healthChecks pass against the Service
+	ConditionExternallyHealthy = "ExternallyHealthy"
+	// ConditionPolicyViolation is True when the App breaks the policy of the operator,
+	// with the violations in the message
+	ConditionPolicyViolation = "PolicyViolation"
+	// ConditionMesh reports whether spec.mesh is applied
+	ConditionMesh = "Mesh"
+	// ConditionCertificateReady reports whether the certificate of spec.tls is issued and valid
+	ConditionCertificateReady = "CertificateReady"
+)
+
 // AppStatus defines the observed state of App
 // type AppStatus struct {
 // 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -60,11 +741,121 @@ type AppStatus struct {
 	// Conditions of the app
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// ObservedGeneration is the generation of the App last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// ReadyReplicas shows how many pods are ready
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 
+	// UpdatedReplicas shows how many pods run the current pod template,
+	// once the Deployment controller has observed it
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
 	// Phase e.g. Pending, Running, Failed
 	Phase string `json:"phase,omitempty"`
+
+	// Usage of the app container over the sampling window of the operator,
+	// when the metrics.k8s.io API is served
+	// +optional
+	Usage *ResourceUsage `json:"usage,omitempty"`
+
+	// AutoTune reports the requests set by spec.resources.autoTune
+	// +optional
+	AutoTune *AutoTuneStatus `json:"autoTune,omitempty"`
+
+	// TLS reports the certificate issued for spec.tls
+	// +optional
+	TLS *TLSStatus `json:"tls,omitempty"`
+
+	// Inference reports the model served for spec.inference
+	// +optional
+	Inference *InferenceStatus `json:"inference,omitempty"`
+}
+
+// InferenceStatus defines how to reach the model served by the App
+type InferenceStatus struct {
+	// Model served
+	Model string `json:"model"`
+
+	// Endpoint is the base URL of the OpenAI-compatible API in the cluster,
+	// e.g. http://llm-svc.ai.svc:11434/v1
+	Endpoint string `json:"endpoint"`
+}
+
+// TLSStatus defines the certificate issued by cert-manager, as reported by the Certificate
+type TLSStatus struct {
+	// SecretName is the Secret holding the certificate and its key
+	SecretName string `json:"secretName"`
+
+	// NotAfter is the expiry of the current certificate
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// RenewalTime is when cert-manager renews the certificate
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+}
+
+// AutoTuneStatus defines the requests tuned by the operator
+type AutoTuneStatus struct {
+	// Requests of the app container, in place of spec.resources.requests
+	// +optional
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+
+	// History of the last changes of the requests, most recent last
+	// +optional
+	History []RequestsChange `json:"history,omitempty"`
+}
+
+// RequestsChange is a change of the requests made by the operator
+type RequestsChange struct {
+	// Time of the change
+	Time metav1.Time `json:"time"`
+
+	// Before are the requests before the change
+	// +optional
+	Before corev1.ResourceList `json:"before,omitempty"`
+
+	// After are the requests after the change
+	After corev1.ResourceList `json:"after"`
+
+	// Message explains the change
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ResourceUsage reports the usage of the app container across the pods of the App
+type ResourceUsage struct {
+	// Samples is the number of samples in the window
+	Samples int32 `json:"samples"`
+
+	// CPU usage
+	CPU ResourceStats `json:"cpu"`
+
+	// Memory usage
+	Memory ResourceStats `json:"memory"`
+}
+
+// ResourceStats are percentiles of the usage of a resource by one pod
+type ResourceStats struct {
+	// P50 is the median usage
+	P50 resource.Quantity `json:"p50"`
+
+	// P95 is the 95th percentile of the usage
+	P95 resource.Quantity `json:"p95"`
+
+	// Request of the app container
+	// +optional
+	Request *resource.Quantity `json:"request,omitempty"`
+
+	// RequestRatio is the p95 usage divided by the request, e.g. "0.25"
+	// +optional
+	RequestRatio string `json:"requestRatio,omitempty"`
+
+	// Recommendation is the request fitting the p95 usage with some headroom,
+	// set when the app container is over-provisioned
+	// +optional
+	Recommendation *resource.Quantity `json:"recommendation,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -88,6 +879,63 @@ type AppList struct {
 	Items           []App `json:"items"`
 }
 
+// RestartedAtAnnotation on an App triggers a rolling restart of its pods
+// whenever its value changes. The value is usually an RFC3339 timestamp.
+const RestartedAtAnnotation = "apps.test.local/restartedAt"
+
+// DeploymentName returns the name of the Deployment owned by the App
+func (a *App) DeploymentName() string {
+	return a.Name + "-app"
+}
+
+// ServiceName returns the name of the Service owned by the App
+func (a *App) ServiceName() string {
+	return a.Name + "-svc"
+}
+
+// InMaintenance reports whether the Service routes to the maintenance backend
+func (a *App) InMaintenance() bool {
+	return a.Spec.Maintenance != nil && a.Spec.Maintenance.Enabled
+}
+
+// SecretName returns the name of the Secret holding the values of spec.secretsFrom
+func (a *App) SecretName() string {
+	return a.Name + "-secrets"
+}
+
+// IngressName returns the name of the Ingress owned by the App
+func (a *App) IngressName() string {
+	return a.Name + "-ingress"
+}
+
+// CertificateName returns the name of the cert-manager Certificate owned by
+// the App, also the name of the Secret holding the certificate
+func (a *App) CertificateName() string {
+	return a.Name + "-tls"
+}
+
+// ServiceAccountName returns the name of the ServiceAccount owned by the App,
+// also the name of its Role and RoleBinding
+func (a *App) ServiceAccountName() string {
+	return a.Name + "-sa"
+}
+
+// ModelCacheName returns the name of the PersistentVolumeClaim holding the
+// models of spec.inference
+func (a *App) ModelCacheName() string {
+	return a.Name + "-models"
+}
+
+// MonitorName returns the name of the ServiceMonitor or PodMonitor owned by the App
+func (a *App) MonitorName() string {
+	return a.Name + "-monitor"
+}
+
+// SelectorLabels returns the labels selecting the pods of the App
+func (a *App) SelectorLabels() map[string]string {
+	return map[string]string{"app": a.Name}
+}
+
 func init() {
 	SchemeBuilder.Register(&App{}, &AppList{})
 }
@@ -0,0 +1,194 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// AppContainerName is the name of the container running spec.image
+const AppContainerName = "app"
+
+// TLSVolumeName is the name of the pod volume holding the certificate of spec.tls
+const TLSVolumeName = "tls"
+
+const (
+	// ModelsVolumeName is the name of the pod volume holding the models of spec.inference
+	ModelsVolumeName = "models"
+	// PullModelContainerName is the name of the init container pulling the model of spec.inference
+	PullModelContainerName = "pull-model"
+)
+
+// Validate checks the rules of the spec the CRD schema cannot express.
+// path is the path of the spec, e.g. field.NewPath("spec").
+func (s *AppSpec) Validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	volumes := sets.New[string]()
+	for i, v := range s.Volumes {
+		volumes.Insert(v.Name)
+		if n := v.sources(); n != 1 {
+			errs = append(errs, field.Invalid(path.Child("volumes").Index(i), v.Name,
+				"exactly one of configMap, secret, emptyDir, persistentVolumeClaim or projected must be set"))
+		}
+	}
+
+	errs = append(errs, validateMounts(s.VolumeMounts, volumes, path.Child("volumeMounts"))...)
+
+	containers := sets.New(AppContainerName)
+	if s.Inference != nil {
+		containers.Insert(PullModelContainerName)
+		for i, v := range s.Volumes {
+			if v.Name == ModelsVolumeName {
+				errs = append(errs, field.Invalid(path.Child("volumes").Index(i).Child("name"), v.Name, "reserved for the models of spec.inference"))
+			}
+		}
+	}
+	for _, c := range []struct {
+		list []corev1.Container
+		path *field.Path
+	}{
+		{s.InitContainers, path.Child("initContainers")},
+		{s.Sidecars, path.Child("sidecars")},
+	} {
+		for i, container := range c.list {
+			if containers.Has(container.Name) {
+				errs = append(errs, field.Duplicate(c.path.Index(i).Child("name"), container.Name))
+			}
+			containers.Insert(container.Name)
+			errs = append(errs, validateMounts(container.VolumeMounts, volumes, c.path.Index(i).Child("volumeMounts"))...)
+		}
+	}
+
+	if s.TLS != nil {
+		for i, v := range s.Volumes {
+			if v.Name == TLSVolumeName {
+				errs = append(errs, field.Invalid(path.Child("volumes").Index(i).Child("name"), v.Name, "reserved for the certificate of spec.tls"))
+			}
+		}
+		if len(s.TLS.DNSNames) == 0 && s.Ingress == nil {
+			errs = append(errs, field.Required(path.Child("tls", "dnsNames"), "required without spec.ingress"))
+		}
+	}
+
+	if s.ServiceAccount != nil {
+		for i, rule := range s.ServiceAccount.Rules {
+			if len(rule.NonResourceURLs) > 0 {
+				errs = append(errs, field.Forbidden(path.Child("serviceAccount", "rules").Index(i).Child("nonResourceURLs"),
+					"non-resource URLs are granted by ClusterRoles only"))
+			}
+		}
+	}
+
+	if s.Service != nil {
+		for i, p := range s.Service.Ports {
+			for _, msg := range validation.IsValidPortName(p.Name) {
+				errs = append(errs, field.Invalid(path.Child("service", "ports").Index(i).Child("name"), p.Name, msg))
+			}
+		}
+	}
+
+	if s.HealthChecks != nil {
+		errs = append(errs, s.validateHealthChecks(path.Child("healthChecks", "checks"))...)
+	}
+	return errs
+}
+
+// validateHealthChecks checks that each health check has one kind and targets
+// a port of the Service
+func (s *AppSpec) validateHealthChecks(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	ports := sets.New("http")
+	if s.Service != nil && len(s.Service.Ports) > 0 {
+		ports = sets.New[string]()
+		for _, p := range s.Service.Ports {
+			ports.Insert(p.Name)
+		}
+	}
+	for i, c := range s.HealthChecks.Checks {
+		if n := c.kinds(); n != 1 {
+			errs = append(errs, field.Invalid(path.Index(i), c.Name, "exactly one of http, tcp or grpc must be set"))
+		}
+		if c.Port != "" && !ports.Has(c.Port) {
+			errs = append(errs, field.NotFound(path.Index(i).Child("port"), c.Port))
+		}
+	}
+	return errs
+}
+
+// kinds returns the number of kinds of check set
+func (c *HealthCheck) kinds() int {
+	n := 0
+	for _, set := range []bool{c.HTTP != nil, c.TCP != nil, c.GRPC != nil} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// VisitContainers calls visit with each container of the pods of the App and
+// the path of its definition: the app container, made of the fields of the
+// spec, then the init containers, the sidecars and the init container pulling
+// the model of spec.inference, which runs the image of the app container.
+func (s *AppSpec) VisitContainers(path *field.Path, visit func(c *corev1.Container, path *field.Path)) {
+	app := corev1.Container{Name: AppContainerName, Image: s.Image, SecurityContext: s.SecurityContext}
+	if s.Resources != nil {
+		app.Resources = corev1.ResourceRequirements{Requests: s.Resources.Requests, Limits: s.Resources.Limits}
+	} else if s.Inference != nil {
+		app.Resources = s.Inference.Profile.Resources()
+	}
+	visit(&app, path)
+	for i := range s.InitContainers {
+		visit(&s.InitContainers[i], path.Child("initContainers").Index(i))
+	}
+	for i := range s.Sidecars {
+		visit(&s.Sidecars[i], path.Child("sidecars").Index(i))
+	}
+	if s.Inference != nil {
+		pull := app
+		pull.Name = PullModelContainerName
+		visit(&pull, path.Child("inference"))
+	}
+}
+
+// validateMounts checks that the mounts reference declared volumes
+func validateMounts(mounts []corev1.VolumeMount, volumes sets.Set[string], path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, m := range mounts {
+		if !volumes.Has(m.Name) {
+			errs = append(errs, field.NotFound(path.Index(i).Child("name"), m.Name))
+		}
+	}
+	return errs
+}
+
+// sources returns the number of sources set on the volume
+func (v *Volume) sources() int {
+	n := 0
+	for _, set := range []bool{
+		v.ConfigMap != nil, v.Secret != nil, v.EmptyDir != nil, v.PersistentVolumeClaim != nil, v.Projected != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
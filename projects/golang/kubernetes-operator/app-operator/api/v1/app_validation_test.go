@@ -0,0 +1,132 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestAppSpecValidate(t *testing.T) {
+	config := Volume{Name: "config", ConfigMap: &corev1.ConfigMapVolumeSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"},
+	}}
+	tests := []struct {
+		name string
+		spec AppSpec
+		want []string
+	}{
+		{name: "no volumes"},
+		{
+			name: "mounts of declared volumes",
+			spec: AppSpec{
+				Volumes:        []Volume{config, {Name: "cache", EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				VolumeMounts:   []corev1.VolumeMount{{Name: "config", MountPath: "/etc/web"}},
+				InitContainers: []corev1.Container{{Name: "warm", VolumeMounts: []corev1.VolumeMount{{Name: "cache"}}}},
+				Sidecars:       []corev1.Container{{Name: "proxy", VolumeMounts: []corev1.VolumeMount{{Name: "config"}}}},
+			},
+		},
+		{
+			name: "undeclared volumes",
+			spec: AppSpec{
+				Volumes:      []Volume{config},
+				VolumeMounts: []corev1.VolumeMount{{Name: "data"}},
+				Sidecars:     []corev1.Container{{Name: "proxy", VolumeMounts: []corev1.VolumeMount{{Name: "certs"}}}},
+			},
+			want: []string{"spec.volumeMounts[0].name", "spec.sidecars[0].volumeMounts[0].name"},
+		},
+		{
+			name: "volume sources",
+			spec: AppSpec{Volumes: []Volume{
+				{Name: "none"},
+				{Name: "two", EmptyDir: &corev1.EmptyDirVolumeSource{}, Secret: &corev1.SecretVolumeSource{SecretName: "web"}},
+			}},
+			want: []string{"spec.volumes[0]", "spec.volumes[1]"},
+		},
+		{
+			name: "duplicate container names",
+			spec: AppSpec{
+				InitContainers: []corev1.Container{{Name: "app"}},
+				Sidecars:       []corev1.Container{{Name: "proxy"}, {Name: "proxy"}},
+			},
+			want: []string{"spec.initContainers[0].name", "spec.sidecars[1].name"},
+		},
+		{
+			name: "health checks",
+			spec: AppSpec{HealthChecks: &HealthChecksSpec{Checks: []HealthCheck{
+				{Name: "http", Port: "http", HTTP: &HTTPHealthCheck{Path: "/healthz"}},
+				{Name: "none"},
+				{Name: "grpc", Port: "grpc", GRPC: &GRPCHealthCheck{}},
+			}}},
+			want: []string{"spec.healthChecks.checks[1]", "spec.healthChecks.checks[2].port"},
+		},
+		{
+			name: "tls",
+			spec: AppSpec{
+				Volumes: []Volume{config, {Name: TLSVolumeName, EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				TLS:     &TLSSpec{IssuerRef: IssuerReference{Name: "letsencrypt"}},
+			},
+			want: []string{"spec.volumes[1].name", "spec.tls.dnsNames"},
+		},
+		{
+			name: "inference",
+			spec: AppSpec{
+				Volumes:        []Volume{{Name: ModelsVolumeName, EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				InitContainers: []corev1.Container{{Name: PullModelContainerName}},
+				Inference:      &InferenceSpec{Model: "llama3.2"},
+			},
+			want: []string{"spec.volumes[0].name", "spec.initContainers[0].name"},
+		},
+		{
+			name: "service port names",
+			spec: AppSpec{Service: &ServiceSpec{Ports: []ServicePort{
+				{Name: "http", Port: 80},
+				{Name: "8443", Port: 8443},
+				{Name: "h2c--grpc", Port: 9090},
+			}}},
+			want: []string{"spec.service.ports[1].name", "spec.service.ports[2].name"},
+		},
+		{
+			name: "service account rules",
+			spec: AppSpec{ServiceAccount: &ServiceAccountSpec{Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+				{NonResourceURLs: []string{"/version"}, Verbs: []string{"get"}},
+			}}},
+			want: []string{"spec.serviceAccount.rules[1].nonResourceURLs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.spec.Validate(field.NewPath("spec"))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Field)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want errors on %v", errs, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("error %d on %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
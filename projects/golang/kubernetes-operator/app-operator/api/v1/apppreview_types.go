@@ -0,0 +1,113 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppPreviewSpec defines the desired state of AppPreview
+type AppPreviewSpec struct {
+	// AppName is the App cloned by the preview, in the namespace of the AppPreview
+	// +kubebuilder:validation:MinLength=1
+	AppName string `json:"appName"`
+
+	// Image overrides the image of the App, e.g. the image built for a pull request
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Domain of the generated hostname <app>-<preview>.<domain>.
+	// Defaults to the parent domain of the App ingress host.
+	// +optional
+	Domain string `json:"domain,omitempty"`
+
+	// TTL after which the preview is deleted, counted from its creation
+	// +kubebuilder:default="72h"
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// Condition types reported in AppPreviewStatus.Conditions
+const (
+	// ConditionPreviewReady reports whether the cloned App is available
+	ConditionPreviewReady = "Ready"
+	// ConditionPreviewReferencesResolved is False when the App references
+	// objects of its namespace that are not copied to the namespace of the
+	// preview, e.g. the Secrets of its environment variables
+	ConditionPreviewReferencesResolved = "ReferencesResolved"
+)
+
+// Labels set on the namespace of a preview, pointing back to the AppPreview
+const (
+	PreviewNameLabel      = "apps.test.local/preview-name"
+	PreviewNamespaceLabel = "apps.test.local/preview-namespace"
+)
+
+// PreviewFinalizer deletes the namespace of a preview before the AppPreview.
+// Cluster-scoped namespaces cannot be garbage-collected through an owner reference
+// to a namespaced object.
+const PreviewFinalizer = "apps.test.local/preview-namespace"
+
+// AppPreviewStatus defines the observed state of AppPreview
+type AppPreviewStatus struct {
+	// Namespace running the cloned App
+	Namespace string `json:"namespace,omitempty"`
+
+	// Host generated for the preview
+	Host string `json:"host,omitempty"`
+
+	// URL of the preview
+	URL string `json:"url,omitempty"`
+
+	// ExpiresAt is the time the preview is deleted
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Phase e.g. Pending, Ready
+	Phase string `json:"phase,omitempty"`
+
+	// Conditions of the preview
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="App",type=string,JSONPath=`.spec.appName`
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`
+
+// AppPreview is the Schema for the apppreviews API
+type AppPreview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppPreviewSpec   `json:"spec,omitempty"`
+	Status AppPreviewStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AppPreviewList contains a list of AppPreview
+type AppPreviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppPreview `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppPreview{}, &AppPreviewList{})
+}
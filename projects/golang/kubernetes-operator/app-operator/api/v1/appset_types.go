@@ -0,0 +1,192 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// AppSetSpec defines the desired state of AppSet
+type AppSetSpec struct {
+	// Template of the App generated in every target namespace, named after the AppSet.
+	// spec.serviceAccount.rules cannot be set: the Apps are written by the operator,
+	// so the rules would not be reviewed against the permissions of the author.
+	// +kubebuilder:validation:XValidation:rule="!has(self.spec.serviceAccount) || !has(self.spec.serviceAccount.rules) || size(self.spec.serviceAccount.rules) == 0",message="spec.serviceAccount.rules cannot be set in an AppSet template"
+	Template AppTemplate `json:"template"`
+
+	// Namespaces targeted by name. Namespaces that do not exist are skipped.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector targets the namespaces matching its labels, in addition to Namespaces
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Overrides of the template for some target namespaces
+	// +listType=map
+	// +listMapKey=namespace
+	// +optional
+	Overrides []AppSetOverride `json:"overrides,omitempty"`
+
+	// Strategy of the rollout of template changes across the targets
+	// +optional
+	Strategy AppSetStrategy `json:"strategy,omitempty"`
+}
+
+// AppTemplate describes the Apps generated by an AppSet
+type AppTemplate struct {
+	// Metadata of the generated Apps
+	// +optional
+	Metadata AppTemplateMetadata `json:"metadata,omitempty"`
+
+	// Spec of the generated Apps
+	Spec AppSpec `json:"spec"`
+}
+
+// AppTemplateMetadata holds the labels and annotations of the generated Apps
+type AppTemplateMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// AppSetOverride replaces fields of the template in one target namespace
+type AppSetOverride struct {
+	// Namespace the override applies to
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Image replaces the image of the template
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Replicas replaces the replicas of the template
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Env is merged into the environment of the template, replacing the variables of the same name
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources replaces the resources of the template
+	// +optional
+	Resources *ResourcesSpec `json:"resources,omitempty"`
+
+	// Ingress replaces the ingress of the template, e.g. to give each tenant its own host
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+}
+
+// AppSetStrategy bounds the disruption of a rollout across the targets
+type AppSetStrategy struct {
+	// MaxUnavailable is the number, or percentage of the targets, of Apps that may be
+	// unavailable while the template is rolled out. At least one App is updated at a time.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// Condition types reported in AppSetStatus.Conditions
+const (
+	// ConditionAppSetReady reports whether every target runs an available App of the current template
+	ConditionAppSetReady = "Ready"
+)
+
+// AppSetNameLabel is set on the Apps generated by an AppSet, with its name
+const AppSetNameLabel = "apps.test.local/appset-name"
+
+// AppSetTemplateHashAnnotation is the hash of the template, overrides included,
+// an App was generated from. A different hash marks the App as outdated.
+const AppSetTemplateHashAnnotation = "apps.test.local/appset-template-hash"
+
+// AppSetTarget is the rollout state of one target namespace
+type AppSetTarget struct {
+	Namespace string `json:"namespace"`
+
+	// Updated is true when the App runs the current template
+	Updated bool `json:"updated"`
+
+	// Available is true when the App has all its replicas ready
+	Available bool `json:"available"`
+
+	// Message explains why the App cannot be generated, e.g. a conflicting App
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// AppSetStatus defines the observed state of AppSet
+type AppSetStatus struct {
+	// ObservedGeneration is the generation of the AppSet last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Targets counts the target namespaces
+	Targets int32 `json:"targets,omitempty"`
+
+	// UpdatedTargets counts the targets running the current template
+	UpdatedTargets int32 `json:"updatedTargets,omitempty"`
+
+	// AvailableTargets counts the targets whose App is available
+	AvailableTargets int32 `json:"availableTargets,omitempty"`
+
+	// Phase e.g. Progressing, Ready
+	Phase string `json:"phase,omitempty"`
+
+	// TargetStatuses is the state of each target, ordered by namespace
+	// +optional
+	TargetStatuses []AppSetTarget `json:"targetStatuses,omitempty"`
+
+	// Conditions of the AppSet
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Targets",type=integer,JSONPath=`.status.targets`
+// +kubebuilder:printcolumn:name="Updated",type=integer,JSONPath=`.status.updatedTargets`
+// +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableTargets`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AppSet is the Schema for the appsets API
+type AppSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppSetSpec   `json:"spec,omitempty"`
+	Status AppSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AppSetList contains a list of AppSet
+type AppSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AppSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AppSet{}, &AppSetList{})
+}
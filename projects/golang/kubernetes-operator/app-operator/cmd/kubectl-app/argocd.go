@@ -0,0 +1,55 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/kstatus"
+)
+
+func newArgoCDHealthCommand() *cobra.Command {
+	kinds := []string{"App", "AppSet", "AppPreview"}
+	cmd := &cobra.Command{
+		Use:   "argocd-health",
+		Short: "Print the argocd-cm patch adding the health checks of the operator resources",
+		Example: `  kubectl app argocd-health > health.yaml
+  kubectl -n argo patch configmap argocd-cm --patch-file health.yaml`,
+		Args: cobra.NoArgs,
+		// Generated offline, without a cluster
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gks := make([]schema.GroupKind, 0, len(kinds))
+			for _, kind := range kinds {
+				gks = append(gks, schema.GroupKind{Group: appv1.GroupVersion.Group, Kind: kind})
+			}
+			out, err := yaml.Marshal(map[string]interface{}{"data": kstatus.ArgoCDConfig(gks...)})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), string(out))
+			return err
+		},
+	}
+	cmd.Flags().StringSliceVar(&kinds, "kind", kinds, "Kinds of the apps.test.local group to check.")
+	return cmd
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func newDescribeCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe NAME",
+		Short: "Show the details, conditions and owned objects of an App",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app, err := o.getApp(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return describe(cmd.Context(), o.client, app, os.Stdout)
+		},
+	}
+}
+
+// getApp fetches an App by name in the selected namespace
+func (o *options) getApp(ctx context.Context, name string) (*appv1.App, error) {
+	app := &appv1.App{}
+	err := o.client.Get(ctx, client.ObjectKey{Namespace: o.namespace, Name: name}, app)
+	return app, err
+}
+
+func describe(ctx context.Context, c client.Client, app *appv1.App, out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", app.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", app.Namespace)
+	fmt.Fprintf(w, "Image:\t%s\n", app.Spec.Image)
+	fmt.Fprintf(w, "Port:\t%d\n", app.Spec.Port)
+	fmt.Fprintf(w, "Phase:\t%s\n", phase(app))
+	fmt.Fprintf(w, "Replicas:\t%d desired | %d ready\n", desiredReplicas(app), app.Status.ReadyReplicas)
+
+	fmt.Fprintln(w, "Conditions:")
+	if len(app.Status.Conditions) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  Type\tStatus\tReason\tMessage")
+		for _, c := range app.Status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+		}
+	}
+
+	fmt.Fprintln(w, "Owned objects:")
+	dep := &appsv1.Deployment{}
+	if err := getOwned(ctx, c, app, app.DeploymentName(), dep); err != nil {
+		fmt.Fprintf(w, "  Deployment/%s\t%s\n", app.DeploymentName(), err)
+	} else {
+		fmt.Fprintf(w, "  Deployment/%s\t%d/%d ready, %d up-to-date\n",
+			dep.Name, dep.Status.ReadyReplicas, dep.Status.Replicas, dep.Status.UpdatedReplicas)
+	}
+
+	svc := &corev1.Service{}
+	if err := getOwned(ctx, c, app, app.ServiceName(), svc); err != nil {
+		fmt.Fprintf(w, "  Service/%s\t%s\n", app.ServiceName(), err)
+	} else {
+		ports := make([]string, 0, len(svc.Spec.Ports))
+		for _, p := range svc.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%s %d/%s", p.Name, p.Port, p.Protocol))
+		}
+		fmt.Fprintf(w, "  Service/%s\t%s %s [%s]\n",
+			svc.Name, svc.Spec.Type, svc.Spec.ClusterIP, strings.Join(ports, ", "))
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(app.Namespace), client.MatchingLabels(app.SelectorLabels())); err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		fmt.Fprintf(w, "  Pod/%s\t%s, %d restarts\n", pod.Name, pod.Status.Phase, restarts(&pod))
+	}
+	return w.Flush()
+}
+
+// getOwned fetches an object created for the App and returns a short
+// description of why it could not be found
+func getOwned(ctx context.Context, c client.Client, app *appv1.App, name string, obj client.Object) error {
+	err := c.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: name}, obj)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("<not found>")
+	}
+	return err
+}
+
+func restarts(pod *corev1.Pod) int32 {
+	var n int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		n += cs.RestartCount
+	}
+	return n
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+	"github.com/balleon/app-operator/internal/controller"
+	"github.com/balleon/app-operator/internal/plan"
+	"github.com/balleon/app-operator/internal/secretstore"
+)
+
+func newDiffCommand(o *options) *cobra.Command {
+	var filename, monitorLabels string
+	cmd := &cobra.Command{
+		Use:   "diff (NAME | -f FILENAME)",
+		Short: "Show the changes the operator would make to the objects owned by an App",
+		Long: `Show the changes the operator would make to the objects owned by an App,
+without making them. The reconciliation of the operator runs locally against
+the cluster and its writes are sent as dry-run requests.`,
+		Example: `  # Impact of a manifest before it is applied
+  kubectl app diff -f app.yaml
+
+  # Drift of the objects owned by a live App
+  kubectl app diff web`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (filename == "") == (len(args) == 0) {
+				return errors.New("either an App name or -f is required")
+			}
+			var apps []*appv1.App
+			if filename != "" {
+				var err error
+				if apps, err = readApps(filename); err != nil {
+					return err
+				}
+			} else {
+				app, err := o.getApp(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				apps = append(apps, app)
+			}
+
+			planner, err := o.planner(monitorLabels)
+			if err != nil {
+				return err
+			}
+			for _, app := range apps {
+				if app.Namespace == "" {
+					app.Namespace = o.namespace
+				}
+				records, err := planner.Plan(cmd.Context(), app)
+				if err != nil {
+					return err
+				}
+				printPlan(os.Stdout, app, records)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "File of the Apps to diff, or - for stdin.")
+	cmd.Flags().StringVar(&monitorLabels, "monitor-labels", "release=kube-prometheus-stack",
+		"The --monitor-labels of the operator, added to the ServiceMonitors and PodMonitors.")
+	return cmd
+}
+
+// planner returns the planner of the operator reconciliation against the cluster
+func (o *options) planner(monitorLabels string) (*plan.Planner, error) {
+	parsedMonitorLabels, err := labels.ConvertSelectorToLabelsMap(monitorLabels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --monitor-labels: %w", err)
+	}
+	apis, err := controller.DetectAPIs(o.clientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+	return &plan.Planner{
+		Client:        o.client,
+		Scheme:        scheme,
+		APIs:          apis,
+		MonitorLabels: parsedMonitorLabels,
+		SecretProviders: map[appv1.SecretProvider]secretstore.Provider{
+			appv1.SecretProviderSecret: secretstore.Secret{Reader: o.client},
+		},
+	}, nil
+}
+
+// readApps reads the Apps of a manifest, - being stdin
+func readApps(filename string) ([]*appv1.App, error) {
+	if filename == "-" {
+		return plan.Decode(os.Stdin)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return plan.Decode(f)
+}
+
+// printPlan prints the changes of the objects owned by app: + for the
+// created objects and fields, ~ for the updated ones and - for the deleted ones
+func printPlan(out io.Writer, app *appv1.App, records []audit.Record) {
+	fmt.Fprintf(out, "App %s/%s\n", app.Namespace, app.Name)
+	changed := false
+	for _, rec := range records {
+		// Updates the API server normalizes back to the live object
+		if rec.Operation != audit.OperationDeleted && len(rec.Changes) == 0 {
+			continue
+		}
+		changed = true
+		mark := "~"
+		switch rec.Operation {
+		case controllerutil.OperationResultCreated:
+			mark = "+"
+		case audit.OperationDeleted:
+			mark = "-"
+		}
+		fmt.Fprintf(out, "%s %s %s (%s)\n", mark, rec.Object.Kind, rec.Object.Name, rec.Operation)
+		for _, c := range rec.Changes {
+			switch {
+			case c.Old == nil:
+				fmt.Fprintf(out, "    + %s: %s\n", c.Path, jsonValue(c.New))
+			case c.New == nil:
+				fmt.Fprintf(out, "    - %s: %s\n", c.Path, jsonValue(c.Old))
+			default:
+				fmt.Fprintf(out, "    ~ %s: %s -> %s\n", c.Path, jsonValue(c.Old), jsonValue(c.New))
+			}
+		}
+	}
+	if !changed {
+		fmt.Fprintln(out, "  No changes")
+	}
+}
+
+// jsonValue formats a field value as JSON, e.g. quoted strings
+func jsonValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
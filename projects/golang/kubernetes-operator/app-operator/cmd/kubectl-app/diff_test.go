@@ -0,0 +1,63 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+)
+
+func TestPrintPlan(t *testing.T) {
+	app := &appv1.App{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	records := []audit.Record{
+		{
+			Object:    audit.ObjectRef{Kind: "Deployment", Name: "web-app"},
+			Operation: controllerutil.OperationResultUpdated,
+			Changes: []audit.Change{
+				{Path: "spec.replicas", Old: int64(2), New: int64(3)},
+				{Path: "spec.template.metadata.labels.team", New: "shop"},
+				{Path: "spec.template.spec.containers[0].args[0]", Old: "--debug"},
+			},
+		},
+		{Object: audit.ObjectRef{Kind: "Service", Name: "web-svc"}, Operation: controllerutil.OperationResultUpdated},
+		{Object: audit.ObjectRef{Kind: "Ingress", Name: "web-ingress"}, Operation: audit.OperationDeleted},
+	}
+	var out bytes.Buffer
+	printPlan(&out, app, records)
+	want := `App default/web
+~ Deployment web-app (updated)
+    ~ spec.replicas: 2 -> 3
+    + spec.template.metadata.labels.team: "shop"
+    - spec.template.spec.containers[0].args[0]: "--debug"
+- Ingress web-ingress (deleted)
+`
+	if out.String() != want {
+		t.Errorf("printPlan =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printPlan(&out, app, records[1:2])
+	if want := "App default/web\n  No changes\n"; out.String() != want {
+		t.Errorf("printPlan = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func newListCommand(o *options) *cobra.Command {
+	var allNamespaces bool
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List Apps with their phase, ready replicas and image",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			apps := &appv1.AppList{}
+			var opts []client.ListOption
+			if !allNamespaces {
+				opts = append(opts, client.InNamespace(o.namespace))
+			}
+			if err := o.client.List(cmd.Context(), apps, opts...); err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			if allNamespaces {
+				fmt.Fprint(w, "NAMESPACE\t")
+			}
+			fmt.Fprintln(w, "NAME\tPHASE\tREADY\tIMAGE\tAGE")
+			for _, app := range apps.Items {
+				if allNamespaces {
+					fmt.Fprintf(w, "%s\t", app.Namespace)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\n",
+					app.Name, phase(&app), app.Status.ReadyReplicas, desiredReplicas(&app),
+					app.Spec.Image, age(app.CreationTimestamp.Time))
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List Apps across all namespaces.")
+	return cmd
+}
+
+// desiredReplicas returns the replicas requested by the App,
+// defaulting to 1 like the Deployment it owns
+func desiredReplicas(app *appv1.App) int32 {
+	if app.Spec.Replicas == nil {
+		return 1
+	}
+	return *app.Spec.Replicas
+}
+
+func phase(app *appv1.App) string {
+	if app.Status.Phase == "" {
+		return "Unknown"
+	}
+	return app.Status.Phase
+}
+
+func age(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func newLogsCommand(o *options) *cobra.Command {
+	var follow bool
+	var tail int64
+	var since time.Duration
+	cmd := &cobra.Command{
+		Use:   "logs NAME",
+		Short: "Print the logs of the app container across all pods of an App",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			app, err := o.getApp(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			pods := &corev1.PodList{}
+			if err := o.client.List(ctx, pods, client.InNamespace(app.Namespace), client.MatchingLabels(app.SelectorLabels())); err != nil {
+				return err
+			}
+			if len(pods.Items) == 0 {
+				return fmt.Errorf("no pods found for app %q", app.Name)
+			}
+
+			logOpts := &corev1.PodLogOptions{Container: appv1.AppContainerName, Follow: follow}
+			if tail >= 0 {
+				logOpts.TailLines = &tail
+			}
+			if since > 0 {
+				seconds := int64(since.Seconds())
+				logOpts.SinceSeconds = &seconds
+			}
+			return o.streamLogs(ctx, pods.Items, logOpts)
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream new log lines.")
+	cmd.Flags().Int64Var(&tail, "tail", -1, "Lines of recent log to display per pod. Defaults to all lines.")
+	cmd.Flags().DurationVar(&since, "since", 0, "Only return logs newer than a relative duration like 5s, 2m, or 3h.")
+	return cmd
+}
+
+// streamLogs copies the logs of every pod to stdout concurrently,
+// prefixing each line with the pod name
+func (o *options) streamLogs(ctx context.Context, pods []corev1.Pod, logOpts *corev1.PodLogOptions) error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(chan error, len(pods))
+	for _, pod := range pods {
+		wg.Add(1)
+		go func(pod corev1.Pod) {
+			defer wg.Done()
+			stream, err := o.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOpts).Stream(ctx)
+			if err != nil {
+				errs <- fmt.Errorf("pod %s: %w", pod.Name, err)
+				return
+			}
+			defer stream.Close()
+			if err := copyLines(stream, os.Stdout, "["+pod.Name+"] ", &mu); err != nil {
+				errs <- fmt.Errorf("pod %s: %w", pod.Name, err)
+			}
+		}(pod)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func copyLines(r io.Reader, w io.Writer, prefix string, mu *sync.Mutex) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		mu.Lock()
+		fmt.Fprintln(w, prefix+scanner.Text())
+		mu.Unlock()
+	}
+	return scanner.Err()
+}
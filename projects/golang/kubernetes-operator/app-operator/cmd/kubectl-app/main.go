@@ -0,0 +1,117 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-app is a kubectl plugin to manage App resources without knowing
+// the names of the objects the operator creates for them.
+// Install it anywhere in PATH and run "kubectl app --help".
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(appv1.AddToScheme(scheme))
+}
+
+// options holds the connection flags shared by all subcommands
+type options struct {
+	kubeconfig string
+	context    string
+	namespace  string
+
+	client    client.Client
+	clientset kubernetes.Interface
+}
+
+// complete builds the API clients from the kubeconfig flags
+func (o *options) complete() error {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.context}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	if o.namespace == "" {
+		ns, _, err := clientConfig.Namespace()
+		if err != nil {
+			return err
+		}
+		o.namespace = ns
+	}
+
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if o.client, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+		return err
+	}
+	o.clientset, err = kubernetes.NewForConfig(cfg)
+	return err
+}
+
+func newRootCommand() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:           "kubectl-app",
+		Short:         "Manage App resources reconciled by the app-operator",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return o.complete()
+		},
+	}
+	cmd.PersistentFlags().StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use.")
+	cmd.PersistentFlags().StringVar(&o.context, "context", "", "The name of the kubeconfig context to use.")
+	cmd.PersistentFlags().StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the App.")
+
+	cmd.AddCommand(
+		newListCommand(o),
+		newDescribeCommand(o),
+		newScaleCommand(o),
+		newSetCommand(o),
+		newRestartCommand(o),
+		newRollbackCommand(o),
+		newLogsCommand(o),
+		newDiffCommand(o),
+		newArgoCDHealthCommand(),
+	)
+	return cmd
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
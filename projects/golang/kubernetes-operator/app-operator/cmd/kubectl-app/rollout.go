@@ -0,0 +1,190 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// revisionAnnotation is set by the Deployment controller on ReplicaSets
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+func newScaleCommand(o *options) *cobra.Command {
+	var replicas int32
+	cmd := &cobra.Command{
+		Use:   "scale NAME --replicas=COUNT",
+		Short: "Set the number of replicas of an App",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.patchApp(cmd.Context(), args[0], func(app *appv1.App) {
+				app.Spec.Replicas = &replicas
+			})
+		},
+	}
+	cmd.Flags().Int32Var(&replicas, "replicas", 1, "The new number of replicas.")
+	_ = cmd.MarkFlagRequired("replicas")
+	return cmd
+}
+
+func newSetCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Update fields of an App",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "image NAME IMAGE",
+		Short: "Update the image of an App",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.patchApp(cmd.Context(), args[0], func(app *appv1.App) {
+				app.Spec.Image = args[1]
+			})
+		},
+	})
+	return cmd
+}
+
+func newRestartCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart NAME",
+		Short: "Restart the pods of an App with a rolling update",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.patchApp(cmd.Context(), args[0], func(app *appv1.App) {
+				metav1.SetMetaDataAnnotation(&app.ObjectMeta, appv1.RestartedAtAnnotation, time.Now().Format(time.RFC3339))
+			})
+		},
+	}
+}
+
+func newRollbackCommand(o *options) *cobra.Command {
+	var toRevision int64
+	cmd := &cobra.Command{
+		Use:   "rollback NAME",
+		Short: "Roll the image of an App back to a previous Deployment revision",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			app, err := o.getApp(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			image, err := revisionImage(ctx, o.client, app, toRevision)
+			if err != nil {
+				return err
+			}
+			if image == app.Spec.Image {
+				return fmt.Errorf("app %q already runs image %s", app.Name, image)
+			}
+			return o.patchApp(ctx, app.Name, func(app *appv1.App) {
+				app.Spec.Image = image
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&toRevision, "to-revision", 0, "The revision to roll back to. Defaults to the previous revision.")
+	return cmd
+}
+
+// patchApp applies mutate to the App and sends the change as a merge patch.
+// The operator then rolls the change out to the objects it owns.
+func (o *options) patchApp(ctx context.Context, name string, mutate func(app *appv1.App)) error {
+	app, err := o.getApp(ctx, name)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(app.DeepCopy())
+	mutate(app)
+	if err := o.client.Patch(ctx, app, patch); err != nil {
+		return err
+	}
+	fmt.Printf("app.apps.test.local/%s patched\n", app.Name)
+	return nil
+}
+
+// revisionImage returns the image of the app container in the given revision
+// of the App's Deployment. A zero revision selects the latest one before the
+// current that runs another image than the App, skipping the revisions of the
+// restarts.
+func revisionImage(ctx context.Context, c client.Client, app *appv1.App, revision int64) (string, error) {
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.DeploymentName()}, dep); err != nil {
+		return "", err
+	}
+	rsList := &appsv1.ReplicaSetList{}
+	if err := c.List(ctx, rsList, client.InNamespace(app.Namespace), client.MatchingLabels(app.SelectorLabels())); err != nil {
+		return "", err
+	}
+
+	current, _ := strconv.ParseInt(dep.Annotations[revisionAnnotation], 10, 64)
+	revisions := map[int64]*appsv1.ReplicaSet{}
+	for i := range rsList.Items {
+		rs := &rsList.Items[i]
+		if !metav1.IsControlledBy(rs, dep) {
+			continue
+		}
+		if n, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64); err == nil {
+			revisions[n] = rs
+		}
+	}
+
+	if revision == 0 {
+		previous := make([]int64, 0, len(revisions))
+		for n := range revisions {
+			if n < current {
+				previous = append(previous, n)
+			}
+		}
+		sort.Slice(previous, func(i, j int) bool { return previous[i] > previous[j] })
+		for _, n := range previous {
+			if image, ok := appImage(revisions[n]); ok && image != app.Spec.Image {
+				return image, nil
+			}
+		}
+		return "", fmt.Errorf("no previous revision with another image than %s found for app %q", app.Spec.Image, app.Name)
+	}
+
+	rs, ok := revisions[revision]
+	if !ok {
+		return "", fmt.Errorf("revision %d not found for app %q", revision, app.Name)
+	}
+	image, ok := appImage(rs)
+	if !ok {
+		return "", fmt.Errorf("revision %d has no app container", revision)
+	}
+	return image, nil
+}
+
+// appImage returns the image of the app container of a ReplicaSet
+func appImage(rs *appsv1.ReplicaSet) (string, bool) {
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		if c.Name == appv1.AppContainerName {
+			return c.Image, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestRevisionImage(t *testing.T) {
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appv1.AppSpec{Image: "web:v3"},
+	}
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:        app.DeploymentName(),
+		Namespace:   app.Namespace,
+		UID:         types.UID("dep-uid"),
+		Annotations: map[string]string{revisionAnnotation: "4"},
+	}}
+	objs := []client.Object{dep}
+	// Revision 4 restarted the pods of revision 3
+	for rev, image := range map[int]string{1: "web:v1", 2: "web:v2", 3: "web:v3", 4: "web:v3"} {
+		objs = append(objs, &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        app.DeploymentName() + "-" + strconv.Itoa(rev),
+				Namespace:   app.Namespace,
+				Labels:      app.SelectorLabels(),
+				Annotations: map[string]string{revisionAnnotation: strconv.Itoa(rev)},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       dep.Name,
+					UID:        dep.UID,
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: appv1.AppContainerName, Image: image}},
+			}}},
+		})
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	tests := []struct {
+		name     string
+		revision int64
+		want     string
+		wantErr  bool
+	}{
+		{name: "previous image", revision: 0, want: "web:v2"},
+		{name: "explicit revision", revision: 1, want: "web:v1"},
+		{name: "unknown revision", revision: 7, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := revisionImage(context.Background(), c, app, tt.revision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("revisionImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("revisionImage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
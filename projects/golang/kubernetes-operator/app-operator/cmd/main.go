@@ -17,26 +17,46 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/yaml"
 
 	appsv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
 	"github.com/balleon/app-operator/internal/controller"
+	"github.com/balleon/app-operator/internal/dashboard"
+	"github.com/balleon/app-operator/internal/healthcheck"
+	"github.com/balleon/app-operator/internal/plan"
+	"github.com/balleon/app-operator/internal/policy"
+	"github.com/balleon/app-operator/internal/secretstore"
+	"github.com/balleon/app-operator/internal/tracing"
+	"github.com/balleon/app-operator/internal/usage"
+	webhookv1 "github.com/balleon/app-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -53,22 +73,124 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		if err := render(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
+	var leaderElectionReleaseOnCancel bool
+	var gracefulShutdownTimeout, drainTimeout time.Duration
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var monitorLabels string
+	var otlpEndpoint string
+	var otlpInsecure bool
+	var maxConcurrentReconciles int
+	var rateLimiterBaseDelay, rateLimiterMaxDelay time.Duration
+	var rateLimiterQPS float64
+	var rateLimiterBurst int
+	var shard controller.Shard
+	var vaultDir string
+	var secretsRefreshInterval time.Duration
+	var usageSampleInterval, usageWindow time.Duration
+	var healthCheckInterval time.Duration
+	var dashboardAddr string
+	var appPolicy policy.Policy
+	var policyAction, policyRequiredLabels, policyAllowedRegistries string
+	var auditSink string
+	var dryRun bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&dashboardAddr, "dashboard-bind-address", "0",
+		"The address the read-only App dashboard binds to, e.g. :8082. Use 0 to disable the dashboard. "+
+			"It is served without authentication.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"How long the candidates wait before taking over a lease that is not renewed.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"How long the leader retries renewing its lease before giving it up. Must be less than the lease duration.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"How often the candidates try to acquire the lease and the leader renews it.")
+	flag.BoolVar(&leaderElectionReleaseOnCancel, "leader-elect-release-on-cancel", true,
+		"If set, the leader releases its lease once stopped, after draining its reconciles, "+
+			"so that the next leader takes over without waiting for the lease duration.")
+	flag.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second,
+		"How long the reconciles in flight may run on once the manager is stopped, before the lease is released. "+
+			"Must be less than the terminationGracePeriodSeconds of the pod.")
+	flag.DurationVar(&drainTimeout, "drain-timeout", 25*time.Second,
+		"How long the reconciles in flight may run on once the manager is stopped, before their context is cancelled. "+
+			"Must be less than --graceful-shutdown-timeout, so they end before the lease is released. Use 0 to cancel them at once.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&monitorLabels, "monitor-labels", "release=kube-prometheus-stack",
+		"Comma separated key=value labels added to every ServiceMonitor and PodMonitor created for Apps, "+
+			"so that they match the monitor selectors of the Prometheus instance.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"The OTLP/gRPC collector address (host:port) the reconcile spans are exported to. Leave empty to disable tracing.")
+	flag.BoolVar(&otlpInsecure, "otlp-insecure", false,
+		"If set, spans are exported to the OTLP collector without TLS.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of Apps reconciled in parallel.")
+	flag.DurationVar(&rateLimiterBaseDelay, "rate-limiter-base-delay", 5*time.Millisecond,
+		"The initial delay before retrying a failed App reconciliation. It doubles on every failure.")
+	flag.DurationVar(&rateLimiterMaxDelay, "rate-limiter-max-delay", 1000*time.Second,
+		"The maximum delay before retrying a failed App reconciliation.")
+	flag.Float64Var(&rateLimiterQPS, "rate-limiter-qps", 10,
+		"The overall number of App reconciliations queued per second.")
+	flag.IntVar(&rateLimiterBurst, "rate-limiter-burst", 100,
+		"The number of App reconciliations that can be queued at once above --rate-limiter-qps.")
+	flag.IntVar(&shard.Count, "shard-count", 1,
+		"The number of operator replicas sharing the Apps. Each replica reconciles the Apps of its --shard-id.")
+	flag.IntVar(&shard.ID, "shard-id", 0,
+		"The shard reconciled by this replica, from 0 to --shard-count - 1. "+
+			"Use -1 to take the ordinal of the StatefulSet pod running the operator.")
+	flag.StringVar(&shard.Label, "shard-label", "",
+		"The label whose value is hashed to assign an App to a shard. Apps without it are assigned by namespace/name.")
+	flag.StringVar(&vaultDir, "vault-dir", "",
+		"The directory of the file-backed vault read by the Vault secret provider, holding one JSON document "+
+			"per secret under a directory per namespace. Leave empty to disable the provider.")
+	flag.DurationVar(&secretsRefreshInterval, "secrets-refresh-interval", 5*time.Minute,
+		"How often the secrets of spec.secretsFrom are read again from their stores.")
+	flag.DurationVar(&usageSampleInterval, "usage-sample-interval", time.Minute,
+		"How often the CPU and memory usage of the pods of an App is sampled from the metrics.k8s.io API. "+
+			"Use 0 to disable the usage report.")
+	flag.DurationVar(&usageWindow, "usage-window", 24*time.Hour,
+		"The rolling window of usage samples the percentiles and right-sizing recommendations are computed on.")
+	flag.DurationVar(&healthCheckInterval, "health-check-interval", 30*time.Second,
+		"How often the spec.healthChecks of an App are performed against its Service.")
+	flag.StringVar(&policyAction, "policy-action", string(policy.Enforce),
+		"What happens to an App violating the policy: Enforce rejects it and stops its reconciliation, "+
+			"Audit only reports the violations in its PolicyViolation condition.")
+	flag.StringVar(&policyRequiredLabels, "policy-required-labels", "",
+		"Comma separated labels every App must set. They are copied to the pods of the App.")
+	flag.StringVar(&policyAllowedRegistries, "policy-allowed-registries", "",
+		"Comma separated registries, or registry/repository prefixes, the images of an App are pulled from, "+
+			"e.g. docker.io/library,ghcr.io/acme. Leave empty to allow every registry.")
+	flag.BoolVar(&appPolicy.DisallowPrivileged, "policy-disallow-privileged", false,
+		"If set, Apps cannot run privileged containers.")
+	flag.BoolVar(&appPolicy.RequireNonRoot, "policy-require-non-root", false,
+		"If set, every container of an App must set runAsNonRoot or a non-zero runAsUser.")
+	flag.BoolVar(&appPolicy.RequireLimits, "policy-require-limits", false,
+		"If set, every container of an App must set CPU and memory limits.")
+	flag.StringVar(&auditSink, "audit-sink", "",
+		"Where the audit records of the changes made to the objects owned by Apps are written: stdout, "+
+			"the path of a file they are appended to, or an http(s) URL they are posted to. Leave empty to disable the audit log.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, the changes to the objects owned by Apps and the status updates are sent as dry-run requests "+
+			"and written to the audit sink, stdout by default, without being made. Leader election and webhooks are disabled.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -120,39 +242,195 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if shard.ID < 0 {
+		var err error
+		if shard.ID, err = statefulSetOrdinal(); err != nil {
+			setupLog.Error(err, "unable to derive --shard-id")
+			os.Exit(1)
+		}
+	}
+	if shard.ID >= shard.Count {
+		setupLog.Error(fmt.Errorf("shard %d out of range", shard.ID), "invalid --shard-id", "shardCount", shard.Count)
+		os.Exit(1)
+	}
+	if err := controller.ValidateDrainTimeout(drainTimeout, gracefulShutdownTimeout); err != nil {
+		setupLog.Error(err, "invalid --drain-timeout")
+		os.Exit(1)
+	}
+
+	// Each shard elects its own leader
+	leaderElectionID := "098d18c6.test.local"
+	if shard.Count > 1 {
+		leaderElectionID = fmt.Sprintf("%s-shard-%d", leaderElectionID, shard.ID)
+	}
+
+	appPolicy.Action = policy.Action(policyAction)
+	if appPolicy.Action != policy.Enforce && appPolicy.Action != policy.Audit {
+		setupLog.Error(fmt.Errorf("unknown action %q", policyAction), "invalid --policy-action")
+		os.Exit(1)
+	}
+	appPolicy.RequiredLabels = splitList(policyRequiredLabels)
+	appPolicy.AllowedRegistries = splitList(policyAllowedRegistries)
+
+	parsedMonitorLabels, err := labels.ConvertSelectorToLabelsMap(monitorLabels)
+	if err != nil {
+		setupLog.Error(err, "invalid --monitor-labels")
+		os.Exit(1)
+	}
+
+	// A dry run runs next to the operator, it must not take its lease
+	if dryRun {
+		enableLeaderElection = false
+		if auditSink == "" {
+			auditSink = "stdout"
+		}
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "098d18c6.test.local",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaderElectionID:       leaderElectionID,
+		LeaseDuration:          &leaseDuration,
+		RenewDeadline:          &renewDeadline,
+		RetryPeriod:            &retryPeriod,
+		// Releasing the lease is safe: the program ends right after the
+		// manager, which first waits for the reconciles to be drained.
+		LeaderElectionReleaseOnCancel: leaderElectionReleaseOnCancel,
+		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// Optional integrations are enabled when their APIs are served at startup
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	apis, err := controller.DetectAPIs(discoveryClient)
+	if err != nil {
+		setupLog.Error(err, "unable to discover optional APIs")
+		os.Exit(1)
+	}
+	setupLog.Info("reconciling shard", "shard", shard.ID, "shardCount", shard.Count)
+	setupLog.Info("detected optional APIs",
+		"prometheusOperator", apis.PrometheusOperator, "metrics", apis.Metrics, "istio", apis.Istio,
+		"certManager", apis.CertManager)
+
+	// Secrets of other namespaces are read uncached
+	secretProviders := map[appsv1.SecretProvider]secretstore.Provider{
+		appsv1.SecretProviderSecret: secretstore.Secret{Reader: mgr.GetAPIReader()},
+	}
+	if vaultDir != "" {
+		secretProviders[appsv1.SecretProviderVault] = secretstore.File{Root: vaultDir}
+	}
+
+	var usageSource usage.Source
+	if apis.Metrics && usageSampleInterval > 0 {
+		if usageSource, err = usage.NewMetricsAPI(restConfig); err != nil {
+			setupLog.Error(err, "unable to create metrics client")
+			os.Exit(1)
+		}
+	}
+
+	var auditLogger *audit.Logger
+	if auditSink != "" {
+		sink, err := audit.NewSink(auditSink)
+		if err != nil {
+			setupLog.Error(err, "unable to open audit sink")
+			os.Exit(1)
+		}
+		auditLogger = &audit.Logger{Sink: sink, Scheme: mgr.GetScheme(), DryRun: dryRun}
+	}
+
+	// The reconcilers write through the dry-run client, the cache still reads the cluster
+	mgrClient := mgr.GetClient()
+	if dryRun {
+		mgrClient = client.NewDryRunClient(mgrClient)
+		setupLog.Info("dry run: no change is made to the cluster")
+	}
+
+	reconcileTimes := dashboard.NewReconcileTimes()
 	if err = (&controller.AppReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:        mgrClient,
+		Scheme:        mgr.GetScheme(),
+		APIs:          apis,
+		MonitorLabels: parsedMonitorLabels,
+		Shard:         shard,
+
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RateLimiter: controller.NewRateLimiter(
+			rateLimiterBaseDelay, rateLimiterMaxDelay, rateLimiterQPS, rateLimiterBurst),
+
+		SecretProviders:        secretProviders,
+		SecretsRefreshInterval: secretsRefreshInterval,
+
+		Usage:               usageSource,
+		UsageRecorder:       usage.NewRecorder(usageWindow),
+		UsageSampleInterval: usageSampleInterval,
+
+		HealthProber:        healthcheck.NewNetProber(),
+		HealthCheckInterval: healthCheckInterval,
+
+		ReconcileTimes: reconcileTimes,
+
+		Policy: appPolicy,
+		Audit:  auditLogger,
+		DryRun: dryRun,
+
+		DrainTimeout: drainTimeout,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "App")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" && !dryRun {
+		if err = webhookv1.SetupAppWebhookWithManager(mgr, appPolicy); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "App")
+			os.Exit(1)
+		}
+	}
+	// AppPreviews are not sharded: only the first shard reconciles them
+	if shard.ID == 0 {
+		if err = (&controller.AppPreviewReconciler{
+			Client:       mgrClient,
+			Scheme:       mgr.GetScheme(),
+			DrainTimeout: drainTimeout,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AppPreview")
+			os.Exit(1)
+		}
+	}
+	// AppSets span namespaces and are not sharded either
+	if shard.ID == 0 {
+		if err = (&controller.AppSetReconciler{
+			Client:       mgrClient,
+			Scheme:       mgr.GetScheme(),
+			DrainTimeout: drainTimeout,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AppSet")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if dashboardAddr != "0" {
+		if err := mgr.Add(&dashboard.Server{
+			Addr:           dashboardAddr,
+			Reader:         mgr.GetClient(),
+			ReconcileTimes: reconcileTimes,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up dashboard")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -162,9 +440,123 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := ctrl.SetupSignalHandler()
+	// The spans are flushed once the manager stops, also before os.Exit skips
+	// the deferred calls
+	flushTracing := func() {}
+	if otlpEndpoint != "" {
+		shutdownTracing, err := tracing.Setup(ctx, otlpEndpoint, otlpInsecure)
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		flushTracing = func() {
+			// The signal context is done at this point, flush with a fresh one
+			if err := shutdownTracing(context.Background()); err != nil {
+				setupLog.Error(err, "problem flushing spans")
+			}
+		}
+		setupLog.Info("exporting reconcile spans", "endpoint", otlpEndpoint)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	flushTracing()
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// render implements "app-operator render -f app.yaml": it prints the objects
+// owned by the Apps of a manifest as the reconciler builds them, without a cluster
+func render(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	filename := fs.String("f", "", "The file of the Apps to render, or - for stdin.")
+	namespace := fs.String("namespace", "default", "The namespace of the Apps that do not set one.")
+	apiNames := fs.String("apis", "prometheus-operator,istio,cert-manager",
+		"Comma separated optional APIs the objects are rendered for: prometheus-operator, istio and cert-manager.")
+	monitorLabels := fs.String("monitor-labels", "release=kube-prometheus-stack",
+		"Comma separated key=value labels added to every ServiceMonitor and PodMonitor.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *filename == "" {
+		return errors.New("-f is required")
+	}
+
+	var apis controller.AvailableAPIs
+	for _, name := range splitList(*apiNames) {
+		switch name {
+		case "prometheus-operator":
+			apis.PrometheusOperator = true
+		case "istio":
+			apis.Istio = true
+		case "cert-manager":
+			apis.CertManager = true
+		default:
+			return fmt.Errorf("unknown API %q in -apis", name)
+		}
+	}
+	parsedMonitorLabels, err := labels.ConvertSelectorToLabelsMap(*monitorLabels)
+	if err != nil {
+		return fmt.Errorf("invalid -monitor-labels: %w", err)
+	}
+
+	in := os.Stdin
+	if *filename != "-" {
+		if in, err = os.Open(*filename); err != nil {
+			return err
+		}
+		defer in.Close()
+	}
+	apps, err := plan.Decode(in)
+	if err != nil {
+		return err
+	}
+	renderer := &plan.Renderer{Scheme: scheme, APIs: apis, MonitorLabels: parsedMonitorLabels}
+	for _, app := range apps {
+		if app.Namespace == "" {
+			app.Namespace = *namespace
+		}
+		objs, err := renderer.Render(context.Background(), app)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(out, "---\n%s", data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma separated flag value, ignoring empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// statefulSetOrdinal returns the ordinal of the StatefulSet pod running the
+// operator, taken from the hostname suffix (e.g. app-operator-2)
+func statefulSetOrdinal() (int, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return 0, err
+	}
+	i := strings.LastIndex(hostname, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("hostname %q has no ordinal suffix", hostname)
+	}
+	return strconv.Atoi(hostname[i+1:])
+}
@@ -0,0 +1,26 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crd embeds the CustomResourceDefinitions generated by controller-gen,
+// for the tools that apply their schema without a cluster
+package crd
+
+import _ "embed"
+
+// App is the CustomResourceDefinition of the App
+//
+//go:embed bases/apps.test.local_apps.yaml
+var App []byte
@@ -0,0 +1,247 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the changes the operator makes to the objects owned
+// by Apps as structured JSON records, written to a configurable sink.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// OperationDeleted is the operation of the records of deleted objects, next
+// to the results of controllerutil.CreateOrUpdate
+const OperationDeleted controllerutil.OperationResult = "deleted"
+
+// redacted replaces the values of Secret keys in the changes
+const redacted = "<redacted>"
+
+// Record is a change made to an object owned by an App
+type Record struct {
+	Time time.Time `json:"time"`
+	// ReconcileID identifies the reconciliation that made the change
+	ReconcileID types.UID                      `json:"reconcileID,omitempty"`
+	App         AppRef                         `json:"app"`
+	Object      ObjectRef                      `json:"object"`
+	Operation   controllerutil.OperationResult `json:"operation"`
+	// Changes are the fields set, changed or removed, empty for deletions
+	Changes []Change `json:"changes,omitempty"`
+	// DryRun is set on the changes of a dry run, that were not made
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// AppRef identifies the App, and the generation of its spec, that caused the change
+type AppRef struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid,omitempty"`
+	Generation int64     `json:"generation"`
+}
+
+// ObjectRef identifies the changed object
+type ObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// Change is the change of one field, e.g. spec.template.spec.containers[0].image.
+// Old is omitted for fields that are set, New for fields that are removed.
+type Change struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Logger builds the records of the changes and writes them to its sink.
+// A nil Logger records nothing.
+type Logger struct {
+	Sink Sink
+	// Scheme resolves the kind of typed objects
+	Scheme *runtime.Scheme
+	// Clock timestamps the records. Defaults to the real clock.
+	Clock clock.PassiveClock
+	// DryRun marks the records of changes sent as dry-run requests
+	DryRun bool
+}
+
+// Log records the change of an object owned by app. before is nil for
+// created objects and after is nil for deleted ones.
+func (l *Logger) Log(ctx context.Context, app client.Object, op controllerutil.OperationResult, before, after client.Object) error {
+	if l == nil || op == controllerutil.OperationResultNone {
+		return nil
+	}
+	obj := after
+	if obj == nil {
+		obj = before
+	}
+	gvk, err := apiutil.GVKForObject(obj, l.Scheme)
+	if err != nil {
+		return err
+	}
+
+	rec := Record{
+		Time:        l.now(),
+		ReconcileID: controller.ReconcileIDFromContext(ctx),
+		App: AppRef{
+			Namespace:  app.GetNamespace(),
+			Name:       app.GetName(),
+			UID:        app.GetUID(),
+			Generation: app.GetGeneration(),
+		},
+		Object: ObjectRef{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+		},
+		Operation: op,
+		DryRun:    l.DryRun,
+	}
+	if after != nil {
+		old, err := content(before)
+		if err != nil {
+			return err
+		}
+		current, err := content(after)
+		if err != nil {
+			return err
+		}
+		rec.Changes = Diff(old, current)
+		if gvk.Group == "" && gvk.Kind == "Secret" {
+			redactSecret(rec.Changes)
+		}
+	}
+	return l.Sink.Write(ctx, rec)
+}
+
+func (l *Logger) now() time.Time {
+	if l.Clock == nil {
+		return time.Now()
+	}
+	return l.Clock.Now()
+}
+
+// content returns the fields of obj compared by Diff
+func content(obj client.Object) (map[string]interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	// The content of unstructured objects is returned as is
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+	// Fields maintained by the API server, not by the operator
+	delete(m, "apiVersion")
+	delete(m, "kind")
+	delete(m, "status")
+	if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+		for _, f := range []string{"resourceVersion", "generation", "uid", "creationTimestamp", "managedFields", "selfLink"} {
+			delete(metadata, f)
+		}
+	}
+	return m, nil
+}
+
+// redactSecret hides the values of the keys of a Secret, keeping which keys changed
+func redactSecret(changes []Change) {
+	for i, c := range changes {
+		if !strings.HasPrefix(c.Path, "data") && !strings.HasPrefix(c.Path, "stringData") {
+			continue
+		}
+		if c.Old != nil {
+			changes[i].Old = redacted
+		}
+		if c.New != nil {
+			changes[i].New = redacted
+		}
+	}
+}
+
+// Diff returns the leaf fields that differ between two objects in their
+// unstructured form, sorted by path. Lists are compared item by item.
+func Diff(before, after map[string]interface{}) []Change {
+	var changes []Change
+	diffValue("", before, after, &changes)
+	return changes
+}
+
+func diffValue(path string, before, after interface{}, changes *[]Change) {
+	// A map or a list set or removed as a whole is reported field by field
+	bm, bIsMap := before.(map[string]interface{})
+	am, aIsMap := after.(map[string]interface{})
+	if (bIsMap || before == nil) && (aIsMap || after == nil) && (bIsMap || aIsMap) {
+		keys := make([]string, 0, len(bm)+len(am))
+		for k := range bm {
+			keys = append(keys, k)
+		}
+		for k := range am {
+			if _, ok := bm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffValue(joinKey(path, k), bm[k], am[k], changes)
+		}
+		return
+	}
+	bl, bIsList := before.([]interface{})
+	al, aIsList := after.([]interface{})
+	if (bIsList || before == nil) && (aIsList || after == nil) && (bIsList || aIsList) {
+		for i := 0; i < len(bl) || i < len(al); i++ {
+			var b, a interface{}
+			if i < len(bl) {
+				b = bl[i]
+			}
+			if i < len(al) {
+				a = al[i]
+			}
+			diffValue(fmt.Sprintf("%s[%d]", path, i), b, a, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, Change{Path: path, Old: before, New: after})
+	}
+}
+
+// joinKey appends a map key to a path, quoting keys that are not identifiers
+// such as labels and annotations
+func joinKey(path, key string) string {
+	if strings.ContainsAny(key, "./[]") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "web", "team": "shop"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"ports":    []interface{}{"http", "metrics"},
+		},
+	}
+	after := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{"app": "web"},
+			"annotations": map[string]interface{}{"example.com/owner": "shop"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"ports":    []interface{}{"http"},
+		},
+	}
+	want := []Change{
+		{Path: "metadata.annotations[example.com/owner]", New: "shop"},
+		{Path: "metadata.labels.team", Old: "shop"},
+		{Path: "spec.ports[1]", Old: "metrics"},
+		{Path: "spec.replicas", Old: int64(2), New: int64(3)},
+	}
+	if diff := cmp.Diff(want, Diff(before, after)); diff != "" {
+		t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+	}
+	if got := Diff(after, after); len(got) != 0 {
+		t.Errorf("Diff() of equal objects = %v", got)
+	}
+}
+
+func TestLoggerLog(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	l := &Logger{Sink: NewWriterSink(&buf), Scheme: clientgoscheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+	app := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", Generation: 4}}
+
+	before := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-app", Namespace: "default", ResourceVersion: "1"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:1.26"}},
+		}}},
+	}
+	after := before.DeepCopy()
+	after.ResourceVersion = "2"
+	after.Spec.Template.Spec.Containers[0].Image = "nginx:1.27"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-secrets", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+
+	for _, err := range []error{
+		l.Log(ctx, app, controllerutil.OperationResultUpdated, before, after),
+		l.Log(ctx, app, controllerutil.OperationResultNone, before, before),
+		l.Log(ctx, app, controllerutil.OperationResultCreated, nil, secret),
+		l.Log(ctx, app, OperationDeleted, after, nil),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d records, want 3:\n%s", len(lines), buf.String())
+	}
+	var records []Record
+	for _, line := range lines {
+		var rec Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+
+	updated := records[0]
+	wantApp := AppRef{Namespace: "default", Name: "web", UID: "web-uid", Generation: 4}
+	wantObject := ObjectRef{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web-app"}
+	if updated.App != wantApp || updated.Object != wantObject || !updated.Time.Equal(now) {
+		t.Errorf("record = %+v", updated)
+	}
+	wantChanges := []Change{{Path: "spec.template.spec.containers[0].image", Old: "nginx:1.26", New: "nginx:1.27"}}
+	if diff := cmp.Diff(wantChanges, updated.Changes); diff != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", diff)
+	}
+
+	created := records[1]
+	if created.Object.Kind != "Secret" || strings.Contains(lines[1], "hunter2") ||
+		strings.Contains(lines[1], "aHVudGVyMg") || !strings.Contains(lines[1], `"path":"data.password","new":"<redacted>"`) {
+		t.Errorf("secret record = %s", lines[1])
+	}
+
+	if deleted := records[2]; deleted.Operation != OperationDeleted || len(deleted.Changes) != 0 {
+		t.Errorf("deleted record = %+v", deleted)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var got Record
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := Record{Operation: controllerutil.OperationResultCreated, Object: ObjectRef{Kind: "Service", Name: "web-svc"}}
+	if err := sink.Write(context.Background(), rec); err != nil {
+		t.Fatal(err)
+	}
+	if got.Object != rec.Object {
+		t.Errorf("received %+v", got)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Write(context.Background(), rec); err == nil {
+		t.Error("Write() = nil, want an error on 503")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink stores the audit records
+type Sink interface {
+	Write(ctx context.Context, rec Record) error
+}
+
+// NewSink returns the sink of target: stdout, an http(s) URL the records
+// are posted to, or the path of a file the records are appended to
+func NewSink(target string) (Sink, error) {
+	switch {
+	case target == "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		return &HTTPSink{URL: target, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	default:
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return NewWriterSink(f), nil
+	}
+}
+
+// WriterSink writes the records as JSON lines
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a sink writing to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write implements Sink. Records of concurrent reconciles are not interleaved.
+func (s *WriterSink) Write(_ context.Context, rec Record) error {
+	line, err := encode(rec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// HTTPSink posts each record as a JSON document to a webhook receiver
+type HTTPSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// Write implements Sink. Any response other than 2xx is an error.
+func (s *HTTPSink) Write(ctx context.Context, rec Record) error {
+	body, err := encode(rec)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("audit receiver returned %s", resp.Status)
+	}
+	return nil
+}
+
+// encode returns the record as a JSON line, keeping values such as <redacted> readable
+func encode(rec Record) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(rec); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
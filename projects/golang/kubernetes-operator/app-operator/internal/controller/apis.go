@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// Third-party kinds are handled as unstructured objects so the operator
+// does not depend on the Go modules of every project it integrates with.
+var (
+	serviceMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorGVK         = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	podMetricsGVK         = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}
+	virtualServiceGVK     = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "VirtualService"}
+	destinationRuleGVK    = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1beta1", Kind: "DestinationRule"}
+	peerAuthenticationGVK = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1beta1", Kind: "PeerAuthentication"}
+	certificateGVK        = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+)
+
+// AvailableAPIs lists the optional APIs served by the cluster.
+// It is detected once at startup, installing one of them requires
+// restarting the operator.
+type AvailableAPIs struct {
+	// PrometheusOperator is true when ServiceMonitor and PodMonitor are served
+	PrometheusOperator bool
+	// Metrics is true when the metrics.k8s.io API of metrics-server is served
+	Metrics bool
+	// Istio is true when VirtualService, DestinationRule and PeerAuthentication are served
+	Istio bool
+	// CertManager is true when the cert-manager Certificate is served
+	CertManager bool
+}
+
+// DetectAPIs queries the discovery API for the optional APIs the operator integrates with
+func DetectAPIs(dc discovery.DiscoveryInterface) (AvailableAPIs, error) {
+	apis := AvailableAPIs{}
+	var err error
+	if apis.PrometheusOperator, err = kindsServed(dc, serviceMonitorGVK, podMonitorGVK); err != nil {
+		return apis, err
+	}
+	if apis.Metrics, err = kindsServed(dc, podMetricsGVK); err != nil {
+		return apis, err
+	}
+	if apis.Istio, err = kindsServed(dc, virtualServiceGVK, destinationRuleGVK, peerAuthenticationGVK); err != nil {
+		return apis, err
+	}
+	if apis.CertManager, err = kindsServed(dc, certificateGVK); err != nil {
+		return apis, err
+	}
+	return apis, nil
+}
+
+// kindsServed reports whether all kinds are served by the API server
+func kindsServed(dc discovery.DiscoveryInterface, gvks ...schema.GroupVersionKind) (bool, error) {
+	for _, gvk := range gvks {
+		resources, err := dc.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		found := false
+		for _, r := range resources.APIResources {
+			if r.Kind == gvk.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// newUnstructured returns an empty object of the given kind
+func newUnstructured(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
@@ -18,29 +18,106 @@ package controller
 
 import (
 	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+	"github.com/balleon/app-operator/internal/dashboard"
+	"github.com/balleon/app-operator/internal/healthcheck"
+	"github.com/balleon/app-operator/internal/policy"
+	"github.com/balleon/app-operator/internal/secretstore"
+	"github.com/balleon/app-operator/internal/tracing"
+	"github.com/balleon/app-operator/internal/usage"
 )
 
 // AppReconciler reconciles a App object
 type AppReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// APIs lists the optional APIs served by the cluster
+	APIs AvailableAPIs
+	// MonitorLabels are added to every ServiceMonitor and PodMonitor,
+	// e.g. to match the serviceMonitorSelector of kube-prometheus-stack
+	MonitorLabels map[string]string
+
+	// Shard restricts the reconciler to a subset of the Apps
+	Shard Shard
+	// MaxConcurrentReconciles is the number of Apps reconciled in parallel. Defaults to 1.
+	MaxConcurrentReconciles int
+	// RateLimiter limits how fast Apps are requeued. Defaults to the controller-runtime rate limiter.
+	RateLimiter workqueue.RateLimiter
+
+	// SecretProviders read the secrets of spec.secretsFrom
+	SecretProviders map[appv1.SecretProvider]secretstore.Provider
+	// SecretsRefreshInterval is how often the secrets of spec.secretsFrom are read again
+	SecretsRefreshInterval time.Duration
+
+	// Usage samples the resource usage of the pods. Nil disables the usage report.
+	Usage usage.Source
+	// UsageRecorder keeps the samples of the Apps
+	UsageRecorder *usage.Recorder
+	// UsageSampleInterval is the minimum time between two samples of an App
+	UsageSampleInterval time.Duration
+
+	// HealthProber performs spec.healthChecks. Nil disables the checks.
+	HealthProber healthcheck.Prober
+	// HealthCheckInterval is how often spec.healthChecks are performed
+	HealthCheckInterval time.Duration
+
+	// ReconcileTimes records when each App was last reconciled, for the dashboard
+	ReconcileTimes *dashboard.ReconcileTimes
+
+	// Policy is checked before the owned objects are reconciled
+	Policy policy.Policy
+
+	// Audit records the changes made to the owned objects. Nil disables the audit log.
+	Audit *audit.Logger
+	// DryRun is set when Client sends the writes as dry-run requests, whose
+	// effects are not read back
+	DryRun bool
+
+	// DrainTimeout is how long the reconciles in flight may run on after the
+	// manager is stopped, less than the GracefulShutdownTimeout of the manager.
+	// Zero cancels them with the manager.
+	DrainTimeout time.Duration
+
+	// healthCheckRuns throttles spec.healthChecks to HealthCheckInterval
+	healthCheckRuns healthCheckRuns
 }
 
 // +kubebuilder:rbac:groups=apps.test.local,resources=apps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.test.local,resources=apps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.test.local,resources=apps/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -60,23 +137,149 @@ type AppReconciler struct {
 // }
 
 func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "AppReconciler.Reconcile", trace.WithAttributes(
+		attribute.String("app.namespace", req.Namespace),
+		attribute.String("app.name", req.Name),
+	))
+	defer span.End()
+
+	result, err := r.reconcile(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+func (r *AppReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
 	// 1. Fetch the App CR
 	app := &appv1.App{}
 	if err := r.Get(ctx, req.NamespacedName, app); err != nil {
+		if apierrors.IsNotFound(err) && r.UsageRecorder != nil {
+			r.UsageRecorder.Forget(req.NamespacedName)
+		}
+		if apierrors.IsNotFound(err) && r.ReconcileTimes != nil {
+			r.ReconcileTimes.Forget(req.NamespacedName)
+		}
+		if apierrors.IsNotFound(err) {
+			r.healthCheckRuns.forget(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	// Events of owned objects are not filtered by shard
+	if !r.Shard.Owns(app) {
+		return ctrl.Result{}, nil
+	}
+	if r.ReconcileTimes != nil {
+		defer func() { r.ReconcileTimes.Record(req.NamespacedName, time.Now()) }()
+	}
+
+	setPausedCondition(app)
+	if app.Spec.Paused {
+		log.Info("App is paused, owned objects are left untouched")
+		if err := r.updateStatus(ctx, app, "Paused"); err != nil {
+			log.Error(err, "Failed to update App status")
+			return ctrl.Result{}, err
+		}
+		return r.requeue(app), nil
+	}
+
+	// The spec is fixed by a new generation, no need to requeue
+	if !setSpecValidCondition(app) {
+		log.Info("Invalid App spec", "message", meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionSpecValid).Message)
+		if err := r.updateStatus(ctx, app, "Failed"); err != nil {
+			log.Error(err, "Failed to update App status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if !r.setPolicyCondition(app) {
+		log.Info("App violates the policy", "message", meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionPolicyViolation).Message)
+		if err := r.updateStatus(ctx, app, "Failed"); err != nil {
+			log.Error(err, "Failed to update App status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileAutoTune(ctx, app, time.Now()); err != nil {
+		log.Error(err, "Failed to persist tuned requests")
+		return ctrl.Result{}, err
+	}
+
+	secretsChecksum, err := r.reconcileSecrets(ctx, app)
+	if err != nil {
+		log.Error(err, "Failed to reconcile Secret")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileServiceAccount(ctx, app); err != nil {
+		log.Error(err, "Failed to reconcile ServiceAccount")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileModelCache(ctx, app); err != nil {
+		log.Error(err, "Failed to reconcile PersistentVolumeClaim")
+		return ctrl.Result{}, err
+	}
 
 	// 2. Reconcile Deployment
 	dep := r.desiredDeployment(app)
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, dep, func() error {
+	op, err := r.createOrUpdate(ctx, app, dep, func() error {
 		// Mutate: set desired spec (idempotent)
 		dep.Spec.Replicas = app.Spec.Replicas // assumes non-nil or you add default logic
 		dep.Spec.Template.Spec.Containers[0].Image = app.Spec.Image
-		dep.Spec.Template.Spec.Containers[0].Env = app.Spec.Env
+		dep.Spec.Template.Spec.Containers[0].Env = containerEnv(app)
+		dep.Spec.Template.Spec.Containers[0].EnvFrom = containerEnvFrom(app)
+		dep.Spec.Template.Spec.Containers[0].Ports = containerPorts(app)
+		dep.Spec.Template.Spec.Containers[0].VolumeMounts = containerVolumeMounts(app)
+		dep.Spec.Template.Spec.Containers[0].Resources = containerResources(app)
+		dep.Spec.Template.Spec.Containers[0].SecurityContext = app.Spec.SecurityContext
+		dep.Spec.Template.Spec.Containers[0].ReadinessProbe = containerReadinessProbe(app)
+		if recreatePods(app) {
+			dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+		} else if dep.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+			// Back to the default strategy, its parameters are defaulted by the API server
+			dep.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+		}
+		dep.Spec.Template.Spec.ReadinessGates = podReadinessGates(app)
+		// The deprecated field is defaulted from the other one, clear both
+		dep.Spec.Template.Spec.ServiceAccountName = podServiceAccountName(app)
+		dep.Spec.Template.Spec.DeprecatedServiceAccount = podServiceAccountName(app)
+		dep.Spec.Template.Spec.AutomountServiceAccountToken = podAutomountToken(app)
+		// Keep the fields defaulted by the API server when nothing else changed
+		if initContainers := podInitContainers(app); !derivative(initContainers, dep.Spec.Template.Spec.InitContainers) {
+			dep.Spec.Template.Spec.InitContainers = initContainers
+		}
+		if volumes := podVolumes(app); !derivative(volumes, dep.Spec.Template.Spec.Volumes) {
+			dep.Spec.Template.Spec.Volumes = volumes
+		}
+		labels := r.podLabels(app)
+		if _, ok := labels[istioInjectLabel]; !ok {
+			delete(dep.Spec.Template.Labels, istioInjectLabel)
+		}
+		for k, v := range labels {
+			metav1.SetMetaDataLabel(&dep.Spec.Template.ObjectMeta, k, v)
+		}
+		annotations := podAnnotations(app)
+		for k := range dep.Spec.Template.Annotations {
+			if _, ok := annotations[k]; !ok && (isInstrumentationAnnotation(k) || k == linkerdInjectAnnotation) {
+				delete(dep.Spec.Template.Annotations, k)
+			}
+		}
+		for k, v := range annotations {
+			metav1.SetMetaDataAnnotation(&dep.Spec.Template.ObjectMeta, k, v)
+		}
+		if secretsChecksum != "" {
+			metav1.SetMetaDataAnnotation(&dep.Spec.Template.ObjectMeta, secretsHashAnnotation, secretsChecksum)
+		} else {
+			delete(dep.Spec.Template.Annotations, secretsHashAnnotation)
+		}
 		// You can add more (resources, probes, etc.) later
-		return nil
+		// Fails when the Deployment is controlled by another object
+		return ctrl.SetControllerReference(app, dep, r.Scheme)
 	})
 	if err != nil {
 		log.Error(err, "Failed to reconcile Deployment")
@@ -86,34 +289,167 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 	// 3. Reconcile Service
 	svc := r.desiredService(app)
-	op, err = controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
-		svc.Spec.Ports[0].Port = app.Spec.Port
-		svc.Spec.Ports[0].TargetPort = intstr.FromInt32(app.Spec.Port)
-		// Add more ports or type change if needed
-		return nil
-	})
+	deleted, err := r.deleteServiceIfHeadlessChanged(ctx, app, svc)
+	if err != nil {
+		log.Error(err, "Failed to recreate Service")
+		return ctrl.Result{}, err
+	}
+	desiredSvc := svc.DeepCopy()
+	mutate := func() error {
+		mutateService(svc, desiredSvc)
+		return ctrl.SetControllerReference(app, svc, r.Scheme)
+	}
+	if deleted && r.DryRun {
+		// The live Service is still there, the Service is recorded as
+		// created without sending the request the API server would reject
+		op, err = controllerutil.OperationResultCreated, mutate()
+		if err == nil {
+			r.recordChange(ctx, app, op, nil, svc)
+		}
+	} else {
+		op, err = r.createOrUpdate(ctx, app, svc, mutate)
+	}
 	if err != nil {
 		log.Error(err, "Failed to reconcile Service")
 		return ctrl.Result{}, err
 	}
 	log.Info("Service reconciled", "operation", op, "name", svc.Name)
+	app.Status.Inference = inferenceStatus(app)
 
-	// 4. Update status (simple version; improve with conditions later)
-	// For better status, re-fetch dep to get latest .Status
-	if err := r.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
-		log.Error(err, "Failed to refresh Deployment status")
-		// continue anyway
+	if err := r.reconcileIngress(ctx, app); err != nil {
+		log.Error(err, "Failed to reconcile Ingress")
+		return ctrl.Result{}, err
 	}
 
-	app.Status.Phase = "Running" // or compute from conditions
-	app.Status.ReadyReplicas = dep.Status.ReadyReplicas
+	if err := r.reconcileTLS(ctx, app); err != nil {
+		log.Error(err, "Failed to reconcile Certificate")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileMonitoring(ctx, app); err != nil {
+		log.Error(err, "Failed to reconcile monitor")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileMesh(ctx, app); err != nil {
+		log.Error(err, "Failed to reconcile mesh traffic policy")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileHealthChecks(ctx, app); err != nil {
+		log.Error(err, "Failed to update pod readiness gates")
+		return ctrl.Result{}, err
+	}
 
-	if err := r.Status().Update(ctx, app); err != nil {
+	// 4. Update status
+	setMaintenanceCondition(app)
+	phase := "Running"
+	if app.InMaintenance() {
+		phase = "Maintenance"
+	}
+	if err := r.updateStatus(ctx, app, phase); err != nil {
 		log.Error(err, "Failed to update App status")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return r.requeue(app), nil
+}
+
+// requeue returns when the App must be reconciled again to poll the
+// secret stores, the metrics API and the health checks
+func (r *AppReconciler) requeue(app *appv1.App) ctrl.Result {
+	var after time.Duration
+	poll := func(interval time.Duration) {
+		if interval > 0 && (after == 0 || interval < after) {
+			after = interval
+		}
+	}
+	if len(app.Spec.SecretsFrom) > 0 && !app.Spec.Paused {
+		poll(r.SecretsRefreshInterval)
+	}
+	if r.Usage != nil {
+		poll(r.UsageSampleInterval)
+	}
+	if checks := app.Spec.HealthChecks; checks != nil && r.HealthProber != nil && !app.Spec.Paused {
+		poll(r.HealthCheckInterval)
+		// Pods wait for the readiness gate until the next checks
+		if checks.ReadinessGate && app.Status.ReadyReplicas < ptr.Deref(app.Spec.Replicas, 1) {
+			poll(healthCheckGateRetryInterval)
+		}
+	}
+	return ctrl.Result{RequeueAfter: after}
+}
+
+// updateStatus sets the phase of the App and refreshes its ready replicas
+// from the Deployment and its usage from the metrics API
+func (r *AppReconciler) updateStatus(ctx context.Context, app *appv1.App, phase string) error {
+	r.reconcileUsage(ctx, app)
+
+	dep := &appsv1.Deployment{}
+	key := client.ObjectKey{Namespace: app.Namespace, Name: app.DeploymentName()}
+	if err := r.Get(ctx, key, dep); client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Failed to refresh Deployment status")
+		// continue anyway
+	}
+
+	app.Status.Phase = phase
+	app.Status.ObservedGeneration = app.Generation
+	app.Status.ReadyReplicas = dep.Status.ReadyReplicas
+	app.Status.UpdatedReplicas = 0
+	if dep.Status.ObservedGeneration >= dep.Generation {
+		app.Status.UpdatedReplicas = dep.Status.UpdatedReplicas
+	}
+	setKstatusConditions(app, dep)
+	return r.Status().Update(ctx, app)
+}
+
+// setSpecValidCondition reports the result of AppSpec.Validate in the
+// SpecValid condition and returns whether the spec is valid
+func setSpecValidCondition(app *appv1.App) bool {
+	cond := metav1.Condition{
+		Type:               appv1.ConditionSpecValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Valid",
+		ObservedGeneration: app.Generation,
+	}
+	if errs := app.Spec.Validate(field.NewPath("spec")); len(errs) > 0 {
+		cond.Status, cond.Reason = metav1.ConditionFalse, "InvalidSpec"
+		cond.Message = errs.ToAggregate().Error()
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
+	return cond.Status == metav1.ConditionTrue
+}
+
+// setPausedCondition reports spec.paused in the Paused condition
+func setPausedCondition(app *appv1.App) {
+	cond := metav1.Condition{
+		Type:               appv1.ConditionPaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Reconciling",
+		Message:            "Owned objects are reconciled",
+		ObservedGeneration: app.Generation,
+	}
+	if app.Spec.Paused {
+		cond.Status, cond.Reason = metav1.ConditionTrue, "PausedBySpec"
+		cond.Message = "Owned objects are left untouched until spec.paused is unset"
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
+}
+
+// setMaintenanceCondition reports spec.maintenance in the Maintenance condition
+func setMaintenanceCondition(app *appv1.App) {
+	cond := metav1.Condition{
+		Type:               appv1.ConditionMaintenance,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Serving",
+		Message:            "The Service routes to the app",
+		ObservedGeneration: app.Generation,
+	}
+	if app.InMaintenance() {
+		cond.Status, cond.Reason = metav1.ConditionTrue, "MaintenanceEnabled"
+		cond.Message = "The Service routes to the maintenance backend"
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -124,19 +460,41 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 // }
 
 func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&appv1.App{}).
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&appv1.App{}, builder.WithPredicates(predicate.NewPredicateFuncs(r.Shard.Owns))).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
-		Complete(r)
+		Owns(&networkingv1.Ingress{}).
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		})
+	if r.APIs.PrometheusOperator {
+		b = b.Owns(newUnstructured(serviceMonitorGVK, "", "")).
+			Owns(newUnstructured(podMonitorGVK, "", ""))
+	}
+	if r.APIs.Istio {
+		b = b.Owns(newUnstructured(virtualServiceGVK, "", "")).
+			Owns(newUnstructured(destinationRuleGVK, "", "")).
+			Owns(newUnstructured(peerAuthenticationGVK, "", ""))
+	}
+	if r.APIs.CertManager {
+		b = b.Owns(newUnstructured(certificateGVK, "", ""))
+	}
+	return b.Complete(drain(r, r.DrainTimeout))
 }
 
 func (r *AppReconciler) desiredDeployment(app *appv1.App) *appsv1.Deployment {
-	labels := map[string]string{"app": app.Name}
+	labels := app.SelectorLabels()
 
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      app.Name + "-app",
+			Name:      app.DeploymentName(),
 			Namespace: app.Namespace,
 			Labels:    labels,
 		},
@@ -144,47 +502,335 @@ func (r *AppReconciler) desiredDeployment(app *appv1.App) *appsv1.Deployment {
 			Selector: &metav1.LabelSelector{MatchLabels: labels},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      r.podLabels(app),
+					Annotations: podAnnotations(app),
 				},
 				Spec: corev1.PodSpec{
+					InitContainers: podInitContainers(app),
+					Volumes:        podVolumes(app),
 					Containers: []corev1.Container{{
-						Name:  "app",
-						Image: app.Spec.Image, // will be overridden in mutate if changed
-						Ports: []corev1.ContainerPort{{
-							ContainerPort: app.Spec.Port,
-						}},
-						Env: app.Spec.Env, // will be overridden in mutate
+						Name:            appv1.AppContainerName,
+						Image:           app.Spec.Image, // will be overridden in mutate if changed
+						Ports:           containerPorts(app),
+						Env:             containerEnv(app), // will be overridden in mutate
+						EnvFrom:         containerEnvFrom(app),
+						VolumeMounts:    containerVolumeMounts(app),
+						Resources:       containerResources(app),
+						SecurityContext: app.Spec.SecurityContext,
+						ReadinessProbe:  containerReadinessProbe(app),
 					}},
+					ReadinessGates:               podReadinessGates(app),
+					ServiceAccountName:           podServiceAccountName(app),
+					AutomountServiceAccountToken: podAutomountToken(app),
 				},
 			},
 		},
 	}
 
+	if recreatePods(app) {
+		dep.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+	}
+
 	// Always set owner reference (garbage collection + event trigger)
 	ctrl.SetControllerReference(app, dep, r.Scheme)
 	return dep
 }
 
 func (r *AppReconciler) desiredService(app *appv1.App) *corev1.Service {
-	labels := map[string]string{"app": app.Name}
+	labels := app.SelectorLabels()
 
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      app.Name + "-svc",
+			Name:      app.ServiceName(),
 			Namespace: app.Namespace,
 			Labels:    labels,
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: labels,
-			Ports: []corev1.ServicePort{{
-				Protocol:   corev1.ProtocolTCP,
-				Port:       80,                   // default; mutate will set to spec.port
-				TargetPort: intstr.FromInt32(80), // default; mutate overrides
-			}},
-			Type: corev1.ServiceTypeClusterIP,
+			Selector:        labels,
+			Type:            corev1.ServiceTypeClusterIP,
+			SessionAffinity: corev1.ServiceAffinityNone,
 		},
 	}
 
+	spec := app.Spec.Service
+	if spec != nil {
+		svc.Annotations = spec.Annotations
+		switch spec.Type {
+		case appv1.ServiceTypeNodePort:
+			svc.Spec.Type = corev1.ServiceTypeNodePort
+		case appv1.ServiceTypeLoadBalancer:
+			svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		case appv1.ServiceTypeHeadless:
+			svc.Spec.ClusterIP = corev1.ClusterIPNone
+		}
+		if spec.SessionAffinity != "" {
+			svc.Spec.SessionAffinity = spec.SessionAffinity
+		}
+	}
+
+	if app.InMaintenance() {
+		svc.Spec.Selector = app.Spec.Maintenance.Selector
+	}
+
+	for _, p := range servicePorts(app) {
+		port := corev1.ServicePort{
+			Name:        p.Name,
+			Protocol:    p.Protocol,
+			AppProtocol: p.AppProtocol,
+			Port:        p.Port,
+			TargetPort:  intstr.FromInt32(p.TargetPort),
+		}
+		// Node ports are only valid on NodePort and LoadBalancer Services
+		if svc.Spec.Type != corev1.ServiceTypeClusterIP {
+			port.NodePort = p.NodePort
+		}
+		svc.Spec.Ports = append(svc.Spec.Ports, port)
+	}
+
 	ctrl.SetControllerReference(app, svc, r.Scheme)
 	return svc
 }
+
+// podAnnotations returns the annotations of the pod template.
+// A restart requested on the App is propagated the same way
+// "kubectl rollout restart" does it.
+func podAnnotations(app *appv1.App) map[string]string {
+	annotations := telemetryAnnotations(app)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range meshPodAnnotations(app) {
+		annotations[k] = v
+	}
+	if restartedAt, ok := app.Annotations[appv1.RestartedAtAnnotation]; ok {
+		annotations["kubectl.kubernetes.io/restartedAt"] = restartedAt
+	}
+	return annotations
+}
+
+// managedAnnotationsAnnotation lists the annotations of spec.service set on
+// the Service, so that the ones removed from the App are removed from the
+// Service while those of other controllers are kept
+const managedAnnotationsAnnotation = "apps.test.local/managed-annotations"
+
+// mutateService copies the desired Service state onto the live object while
+// keeping the fields allocated by the cluster (cluster IP, node ports).
+func mutateService(svc, desired *corev1.Service) {
+	if svc.Labels == nil {
+		svc.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		svc.Labels[k] = v
+	}
+	for _, k := range strings.Split(svc.Annotations[managedAnnotationsAnnotation], ",") {
+		if _, ok := desired.Annotations[k]; !ok {
+			delete(svc.Annotations, k)
+		}
+	}
+	delete(svc.Annotations, managedAnnotationsAnnotation)
+	if len(desired.Annotations) > 0 {
+		managed := make([]string, 0, len(desired.Annotations))
+		for k, v := range desired.Annotations {
+			metav1.SetMetaDataAnnotation(&svc.ObjectMeta, k, v)
+			managed = append(managed, k)
+		}
+		sort.Strings(managed)
+		metav1.SetMetaDataAnnotation(&svc.ObjectMeta, managedAnnotationsAnnotation, strings.Join(managed, ","))
+	}
+
+	// The cluster IP is immutable, so it is only set when creating
+	if svc.CreationTimestamp.IsZero() {
+		svc.Spec.ClusterIP = desired.Spec.ClusterIP
+	}
+	svc.Spec.Type = desired.Spec.Type
+	svc.Spec.Selector = desired.Spec.Selector
+	svc.Spec.SessionAffinity = desired.Spec.SessionAffinity
+	// The config is defaulted by the API server for ClientIP and rejected otherwise
+	if desired.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		svc.Spec.SessionAffinityConfig = nil
+	}
+
+	ports := make([]corev1.ServicePort, 0, len(desired.Spec.Ports))
+	for _, p := range desired.Spec.Ports {
+		if p.NodePort == 0 && desired.Spec.Type != corev1.ServiceTypeClusterIP {
+			// Keep the node port allocated by the cluster
+			for _, live := range svc.Spec.Ports {
+				if live.Name == p.Name {
+					p.NodePort = live.NodePort
+				}
+			}
+		}
+		ports = append(ports, p)
+	}
+	svc.Spec.Ports = ports
+}
+
+// deleteServiceIfHeadlessChanged deletes the live Service when the App switches
+// to or from a headless Service, since the cluster IP cannot be updated in place.
+// It reports whether the Service was deleted.
+func (r *AppReconciler) deleteServiceIfHeadlessChanged(ctx context.Context, app *appv1.App, desired *corev1.Service) (bool, error) {
+	live := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	wantHeadless := desired.Spec.ClusterIP == corev1.ClusterIPNone
+	isHeadless := live.Spec.ClusterIP == corev1.ClusterIPNone
+	if wantHeadless == isHeadless {
+		return false, nil
+	}
+	log.FromContext(ctx).Info("Recreating Service to change cluster IP", "name", live.Name, "headless", wantHeadless)
+	if err := r.deleteObject(ctx, app, live); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return true, nil
+}
+
+// servicePorts returns the ports of the App with defaults applied.
+// Without spec.service.ports, spec.port is exposed over TCP as "http".
+func servicePorts(app *appv1.App) []appv1.ServicePort {
+	if app.Spec.Service == nil || len(app.Spec.Service.Ports) == 0 {
+		return []appv1.ServicePort{{
+			Name:       "http",
+			Port:       app.Spec.Port,
+			TargetPort: app.Spec.Port,
+			Protocol:   corev1.ProtocolTCP,
+		}}
+	}
+
+	ports := make([]appv1.ServicePort, 0, len(app.Spec.Service.Ports))
+	for _, p := range app.Spec.Service.Ports {
+		if p.TargetPort == 0 {
+			p.TargetPort = p.Port
+		}
+		if p.Protocol == "" {
+			p.Protocol = corev1.ProtocolTCP
+		}
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+// containerPorts returns the ports opened by the app container: spec.port
+// plus every distinct target port of the Service.
+func containerPorts(app *appv1.App) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{{
+		ContainerPort: app.Spec.Port,
+		Protocol:      corev1.ProtocolTCP,
+	}}
+	for _, p := range servicePorts(app) {
+		exists := false
+		for i, cp := range ports {
+			if cp.ContainerPort == p.TargetPort && cp.Protocol == p.Protocol {
+				if cp.Name == "" {
+					ports[i].Name = p.Name
+				}
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			ports = append(ports, corev1.ContainerPort{
+				Name:          p.Name,
+				ContainerPort: p.TargetPort,
+				Protocol:      p.Protocol,
+			})
+		}
+	}
+	return ports
+}
+
+// containerResources returns the resources of the app container, with the
+// requests tuned by resources.autoTune
+func containerResources(app *appv1.App) corev1.ResourceRequirements {
+	spec := app.Spec.Resources
+	if spec == nil && app.Spec.Inference != nil {
+		return app.Spec.Inference.Profile.Resources()
+	}
+	if spec == nil {
+		return corev1.ResourceRequirements{}
+	}
+	resources := corev1.ResourceRequirements{
+		Requests: spec.Requests,
+		Limits:   spec.Limits,
+	}
+	if spec.AutoTune != nil && app.Status.AutoTune != nil && len(app.Status.AutoTune.Requests) > 0 {
+		resources.Requests = spec.Requests.DeepCopy()
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		for name, q := range app.Status.AutoTune.Requests {
+			resources.Requests[name] = q
+		}
+	}
+	return resources
+}
+
+// podInitContainers returns the init containers of the pod: spec.initContainers
+// followed by spec.sidecars, turned into native sidecars
+func podInitContainers(app *appv1.App) []corev1.Container {
+	if len(app.Spec.InitContainers)+len(app.Spec.Sidecars) == 0 && app.Spec.Inference == nil {
+		return nil
+	}
+	containers := make([]corev1.Container, 0, len(app.Spec.InitContainers)+len(app.Spec.Sidecars)+1)
+	containers = append(containers, app.Spec.InitContainers...)
+	for _, c := range app.Spec.Sidecars {
+		c.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		containers = append(containers, c)
+	}
+	// Last, the model is pulled through the sidecars, e.g. a proxy
+	if app.Spec.Inference != nil {
+		containers = append(containers, pullModelContainer(app))
+	}
+	return containers
+}
+
+// podVolumes returns the volumes of the pod declared in spec.volumes,
+// followed by the certificate of spec.tls and the models of spec.inference
+func podVolumes(app *appv1.App) []corev1.Volume {
+	if len(app.Spec.Volumes) == 0 && app.Spec.TLS == nil && app.Spec.Inference == nil {
+		return nil
+	}
+	volumes := make([]corev1.Volume, 0, len(app.Spec.Volumes)+2)
+	for _, v := range app.Spec.Volumes {
+		volumes = append(volumes, corev1.Volume{
+			Name: v.Name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap:             v.ConfigMap,
+				Secret:                v.Secret,
+				EmptyDir:              v.EmptyDir,
+				PersistentVolumeClaim: v.PersistentVolumeClaim,
+				Projected:             v.Projected,
+			},
+		})
+	}
+	if app.Spec.TLS != nil {
+		volumes = append(volumes, tlsVolume(app))
+	}
+	if app.Spec.Inference != nil {
+		volumes = append(volumes, modelsVolume(app))
+	}
+	return volumes
+}
+
+// containerVolumeMounts returns the volume mounts of the app container
+// declared in spec.volumeMounts, followed by the certificate of spec.tls and
+// the models of spec.inference
+func containerVolumeMounts(app *appv1.App) []corev1.VolumeMount {
+	if app.Spec.TLS == nil && app.Spec.Inference == nil {
+		return app.Spec.VolumeMounts
+	}
+	mounts := append([]corev1.VolumeMount{}, app.Spec.VolumeMounts...)
+	if app.Spec.TLS != nil {
+		mounts = append(mounts, tlsVolumeMount(app))
+	}
+	if app.Spec.Inference != nil {
+		mounts = append(mounts, modelsVolumeMount())
+	}
+	return mounts
+}
+
+// derivative reports whether the live list only differs from the desired one
+// by the fields the API server defaults
+func derivative[T any](desired, live []T) bool {
+	return len(desired) == len(live) && equality.Semantic.DeepDerivative(desired, live)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+var benchApps = flag.Int("bench-apps", 2000, "number of Apps created by BenchmarkReconcileThroughput")
+
+// BenchmarkReconcileThroughput measures how fast the operator converges a
+// fleet of Apps against envtest, for several concurrency settings:
+//
+//	go test ./internal/controller -run '^$' -bench ReconcileThroughput -benchtime 1x -bench-apps 5000
+func BenchmarkReconcileThroughput(b *testing.B) {
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: filepath.Join("..", "..", "bin", "k8s",
+			fmt.Sprintf("1.30.0-%s-%s", runtime.GOOS, runtime.GOARCH)),
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer func() { _ = testEnv.Stop() }()
+	// The default client-side throttling would dominate the measure
+	cfg.QPS, cfg.Burst = 1000, 2000
+
+	scheme := k8sruntime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appv1.AddToScheme(scheme)
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				ctx, cancel := context.WithCancel(context.Background())
+				ns := fmt.Sprintf("bench-%d-%d", workers, i)
+				if err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}); err != nil {
+					b.Fatal(err)
+				}
+				for n := 0; n < *benchApps; n++ {
+					app := &appv1.App{
+						ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("app-%d", n), Namespace: ns},
+						Spec:       appv1.AppSpec{Image: "nginx:1.27", Port: 80},
+					}
+					if err := c.Create(ctx, app); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+					Scheme:  scheme,
+					Metrics: metricsserver.Options{BindAddress: "0"},
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				err = (&AppReconciler{
+					Client:                  mgr.GetClient(),
+					Scheme:                  mgr.GetScheme(),
+					MaxConcurrentReconciles: workers,
+					RateLimiter:             NewRateLimiter(5*time.Millisecond, time.Minute, 1000, 2000),
+				}).SetupWithManager(mgr)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				b.StartTimer()
+				start := time.Now()
+				go func() { _ = mgr.Start(ctx) }()
+				for {
+					deps := &appsv1.DeploymentList{}
+					if err := mgr.GetAPIReader().List(ctx, deps, client.InNamespace(ns)); err != nil {
+						b.Fatal(err)
+					}
+					if len(deps.Items) >= *benchApps {
+						break
+					}
+					time.Sleep(100 * time.Millisecond)
+				}
+				b.ReportMetric(float64(*benchApps)/time.Since(start).Seconds(), "apps/s")
+				cancel()
+			}
+		})
+	}
+}
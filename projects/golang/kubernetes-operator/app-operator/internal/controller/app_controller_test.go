@@ -18,67 +18,285 @@ package controller
 
 import (
 	"context"
+	"errors"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/api/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	appsv1 "github.com/balleon/app-operator/api/v1"
+	appv1 "github.com/balleon/app-operator/api/v1"
 )
 
 var _ = Describe("App Controller", func() {
-	Context("When reconciling a resource", func() {
-		const resourceName = "test-resource"
+	ctx := context.Background()
+
+	var (
+		app                *appv1.App
+		typeNamespacedName types.NamespacedName
+		reconciler         *AppReconciler
+	)
+
+	reconcileApp := func() error {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		return err
+	}
+
+	getDeployment := func() *appsv1.Deployment {
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: app.DeploymentName()}, dep)).To(Succeed())
+		return dep
+	}
+
+	getService := func() *corev1.Service {
+		svc := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: app.ServiceName()}, svc)).To(Succeed())
+		return svc
+	}
+
+	// updateApp re-reads the App before applying mutate to avoid conflicts
+	// with the status written by the reconciler
+	updateApp := func(mutate func(app *appv1.App)) {
+		Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+		mutate(app)
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		By("creating the custom resource for the Kind App")
+		app = &appv1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-" + rand.String(6),
+				Namespace: "default",
+			},
+			Spec: appv1.AppSpec{
+				Image:    "nginx:1.27",
+				Replicas: ptr.To[int32](2),
+				Port:     8080,
+			},
+		}
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+		typeNamespacedName = client.ObjectKeyFromObject(app)
 
-		ctx := context.Background()
+		reconciler = &AppReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+	})
 
-		typeNamespacedName := types.NamespacedName{
-			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+	AfterEach(func() {
+		By("Cleanup the specific resource instance App")
+		resource := &appv1.App{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 		}
-		app := &appsv1.App{}
-
-		BeforeEach(func() {
-			By("creating the custom resource for the Kind App")
-			err := k8sClient.Get(ctx, typeNamespacedName, app)
-			if err != nil && errors.IsNotFound(err) {
-				resource := &appsv1.App{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      resourceName,
-						Namespace: "default",
+		// envtest runs no garbage collector, owned objects are removed by hand
+		_ = k8sClient.Delete(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: app.Namespace, Name: app.DeploymentName()}})
+		_ = k8sClient.Delete(ctx, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: app.Namespace, Name: app.ServiceName()}})
+	})
+
+	Context("When reconciling a resource", func() {
+		It("should create a Deployment running the App image", func() {
+			Expect(reconcileApp()).To(Succeed())
+
+			dep := getDeployment()
+			Expect(dep.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+			Expect(dep.Spec.Selector.MatchLabels).To(Equal(app.SelectorLabels()))
+			Expect(dep.Spec.Template.Labels).To(Equal(app.SelectorLabels()))
+			Expect(dep.Spec.Template.Spec.Containers).To(HaveLen(1))
+			container := dep.Spec.Template.Spec.Containers[0]
+			Expect(container.Name).To(Equal("app"))
+			Expect(container.Image).To(Equal("nginx:1.27"))
+			Expect(container.Ports).To(ConsistOf(corev1.ContainerPort{
+				Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP,
+			}))
+		})
+
+		It("should create a ClusterIP Service exposing spec.port", func() {
+			Expect(reconcileApp()).To(Succeed())
+
+			svc := getService()
+			Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(svc.Spec.ClusterIP).NotTo(BeEmpty())
+			Expect(svc.Spec.Selector).To(Equal(app.SelectorLabels()))
+			Expect(svc.Spec.Ports).To(HaveLen(1))
+			Expect(svc.Spec.Ports[0].Name).To(Equal("http"))
+			Expect(svc.Spec.Ports[0].Port).To(Equal(int32(8080)))
+			Expect(svc.Spec.Ports[0].TargetPort).To(Equal(intstr.FromInt32(8080)))
+		})
+
+		It("should set the App as controller of the owned objects", func() {
+			Expect(reconcileApp()).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+
+			for _, obj := range []client.Object{getDeployment(), getService()} {
+				owner := metav1.GetControllerOf(obj)
+				Expect(owner).NotTo(BeNil())
+				Expect(owner.Kind).To(Equal("App"))
+				Expect(owner.UID).To(Equal(app.UID))
+				Expect(owner.BlockOwnerDeletion).To(HaveValue(BeTrue()))
+			}
+		})
+
+		It("should propagate updates of the image, env, port and replicas", func() {
+			Expect(reconcileApp()).To(Succeed())
+
+			updateApp(func(app *appv1.App) {
+				app.Spec.Image = "nginx:1.28"
+				app.Spec.Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+				app.Spec.Port = 9090
+				app.Spec.Replicas = ptr.To[int32](3)
+			})
+			Expect(reconcileApp()).To(Succeed())
+
+			dep := getDeployment()
+			Expect(dep.Spec.Replicas).To(HaveValue(Equal(int32(3))))
+			container := dep.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("nginx:1.28"))
+			Expect(container.Env).To(ConsistOf(corev1.EnvVar{Name: "FOO", Value: "bar"}))
+			Expect(container.Ports).To(ConsistOf(corev1.ContainerPort{
+				Name: "http", ContainerPort: 9090, Protocol: corev1.ProtocolTCP,
+			}))
+
+			svc := getService()
+			Expect(svc.Spec.Ports).To(HaveLen(1))
+			Expect(svc.Spec.Ports[0].Port).To(Equal(int32(9090)))
+			Expect(svc.Spec.Ports[0].TargetPort).To(Equal(intstr.FromInt32(9090)))
+		})
+
+		It("should reconcile the full list of Service ports", func() {
+			updateApp(func(app *appv1.App) {
+				app.Spec.Service = &appv1.ServiceSpec{
+					Type: appv1.ServiceTypeNodePort,
+					Ports: []appv1.ServicePort{
+						{Name: "http", Port: 80, TargetPort: 8080, AppProtocol: ptr.To("http")},
+						{Name: "metrics", Port: 9090},
+						{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
 					},
-					// TODO(user): Specify other spec details if needed.
 				}
-				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
-			}
+			})
+			Expect(reconcileApp()).To(Succeed())
+
+			svc := getService()
+			Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeNodePort))
+			Expect(svc.Spec.Ports).To(HaveLen(3))
+			nodePort := svc.Spec.Ports[0].NodePort
+			Expect(nodePort).NotTo(BeZero())
+			Expect(svc.Spec.Ports[0].AppProtocol).To(HaveValue(Equal("http")))
+			Expect(svc.Spec.Ports[2].Protocol).To(Equal(corev1.ProtocolUDP))
+
+			By("removing a port")
+			updateApp(func(app *appv1.App) {
+				app.Spec.Service.Ports = app.Spec.Service.Ports[:1]
+			})
+			Expect(reconcileApp()).To(Succeed())
+
+			svc = getService()
+			Expect(svc.Spec.Ports).To(HaveLen(1))
+			Expect(svc.Spec.Ports[0].NodePort).To(Equal(nodePort), "allocated node ports are kept")
 		})
 
-		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &appsv1.App{}
-			err := k8sClient.Get(ctx, typeNamespacedName, resource)
-			Expect(err).NotTo(HaveOccurred())
+		It("should recreate the Service when switching to headless", func() {
+			Expect(reconcileApp()).To(Succeed())
+			Expect(getService().Spec.ClusterIP).NotTo(Equal(corev1.ClusterIPNone))
 
-			By("Cleanup the specific resource instance App")
-			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			updateApp(func(app *appv1.App) {
+				app.Spec.Service = &appv1.ServiceSpec{Type: appv1.ServiceTypeHeadless}
+			})
+			Expect(reconcileApp()).To(Succeed())
+			Expect(getService().Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
 		})
-		It("should successfully reconcile the resource", func() {
-			By("Reconciling the created resource")
-			controllerReconciler := &AppReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
-			}
 
-			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
-				NamespacedName: typeNamespacedName,
+		It("should report ready replicas and phase in status", func() {
+			Expect(reconcileApp()).To(Succeed())
+
+			dep := getDeployment()
+			dep.Status.Replicas = 2
+			dep.Status.ReadyReplicas = 2
+			dep.Status.AvailableReplicas = 2
+			Expect(k8sClient.Status().Update(ctx, dep)).To(Succeed())
+			Expect(reconcileApp()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+			Expect(app.Status.Phase).To(Equal("Running"))
+			Expect(app.Status.ReadyReplicas).To(Equal(int32(2)))
+		})
+
+		It("should report monitoring as unavailable without prometheus-operator", func() {
+			updateApp(func(app *appv1.App) {
+				app.Spec.Monitoring = &appv1.MonitoringSpec{Port: "http"}
 			})
-			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			Expect(reconcileApp()).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+			cond := meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionMonitoring)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("PrometheusOperatorNotInstalled"))
+		})
+
+		It("should ignore Apps of other shards", func() {
+			reconciler.Shard = Shard{Count: 2}
+			if reconciler.Shard.Owns(app) {
+				reconciler.Shard.ID = 1
+			}
+			Expect(reconcileApp()).To(Succeed())
+
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: app.Namespace, Name: app.DeploymentName()}, &appsv1.Deployment{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When the App is deleted", func() {
+		It("should leave the owned objects to the garbage collector", func() {
+			Expect(reconcileApp()).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+			uid := app.UID
+
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+			Expect(reconcileApp()).To(Succeed())
+
+			// The garbage collector deletes objects whose controller is gone
+			Expect(metav1.GetControllerOf(getDeployment()).UID).To(Equal(uid))
+			Expect(metav1.GetControllerOf(getService()).UID).To(Equal(uid))
+		})
+	})
+
+	Context("When reconciling fails", func() {
+		It("should return an error when the Deployment is controlled by another object", func() {
+			owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: app.Name + "-owner", Namespace: app.Namespace}}
+			Expect(k8sClient.Create(ctx, owner)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, owner)
+
+			dep := reconciler.desiredDeployment(app)
+			dep.OwnerReferences = nil
+			Expect(controllerutil.SetControllerReference(owner, dep, k8sClient.Scheme())).To(Succeed())
+			Expect(k8sClient.Create(ctx, dep)).To(Succeed())
+
+			err := reconcileApp()
+			Expect(err).To(HaveOccurred())
+			var alreadyOwned *controllerutil.AlreadyOwnedError
+			Expect(errors.As(err, &alreadyOwned)).To(BeTrue())
+		})
+
+		It("should reject an App with an out of range replica count", func() {
+			invalid := &appv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: app.Name + "-invalid", Namespace: app.Namespace},
+				Spec:       appv1.AppSpec{Image: "nginx:1.27", Port: 80, Replicas: ptr.To[int32](11)},
+			}
+			err := k8sClient.Create(ctx, invalid)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
 		})
 	})
 })
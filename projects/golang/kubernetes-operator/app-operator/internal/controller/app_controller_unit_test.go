@@ -0,0 +1,619 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// These tests exercise the builders and the reconcile loop against the fake
+// client, they do not need the envtest binaries.
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func newTestApp(mutate func(app *appv1.App)) *appv1.App {
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid"},
+		Spec: appv1.AppSpec{
+			Image:    "nginx:1.27",
+			Replicas: ptr.To[int32](2),
+			Port:     8080,
+		},
+	}
+	if mutate != nil {
+		mutate(app)
+	}
+	return app
+}
+
+func TestDesiredDeployment(t *testing.T) {
+	tests := []struct {
+		name            string
+		app             *appv1.App
+		wantEnv         []corev1.EnvVar
+		wantPorts       []corev1.ContainerPort
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "defaults",
+			app:             newTestApp(nil),
+			wantPorts:       []corev1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+			wantAnnotations: map[string]string{},
+		},
+		{
+			name: "service ports open container ports",
+			app: newTestApp(func(app *appv1.App) {
+				app.Spec.Service = &appv1.ServiceSpec{Ports: []appv1.ServicePort{
+					{Name: "web", Port: 80, TargetPort: 8080},
+					{Name: "metrics", Port: 9090},
+					{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+				}}
+			}),
+			wantPorts: []corev1.ContainerPort{
+				{Name: "web", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+				{Name: "metrics", ContainerPort: 9090, Protocol: corev1.ProtocolTCP},
+				{Name: "dns", ContainerPort: 53, Protocol: corev1.ProtocolUDP},
+			},
+			wantAnnotations: map[string]string{},
+		},
+		{
+			name: "telemetry",
+			app: newTestApp(func(app *appv1.App) {
+				app.Spec.Env = []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: "custom"}}
+				app.Spec.Telemetry = &appv1.TelemetrySpec{
+					Language:        "python",
+					Instrumentation: "otel/instrumentation",
+					Endpoint:        "http://collector:4318",
+					SamplingRatio:   "0.5",
+				}
+			}),
+			wantEnv: []corev1.EnvVar{
+				{Name: "OTEL_SERVICE_NAME", Value: "custom"},
+				{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://collector:4318"},
+				{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+				{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "0.5"},
+			},
+			wantPorts: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+			wantAnnotations: map[string]string{
+				"instrumentation.opentelemetry.io/inject-python": "otel/instrumentation",
+			},
+		},
+		{
+			name: "restart",
+			app: newTestApp(func(app *appv1.App) {
+				app.Annotations = map[string]string{appv1.RestartedAtAnnotation: "2026-01-02T03:04:05Z"}
+			}),
+			wantPorts: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: corev1.ProtocolTCP}},
+			wantAnnotations: map[string]string{
+				"kubectl.kubernetes.io/restartedAt": "2026-01-02T03:04:05Z",
+			},
+		},
+	}
+
+	r := &AppReconciler{Scheme: newTestScheme(t)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dep := r.desiredDeployment(tt.app)
+
+			if dep.Name != "web-app" || dep.Namespace != "default" {
+				t.Errorf("unexpected Deployment %s/%s", dep.Namespace, dep.Name)
+			}
+			if got := metav1.GetControllerOf(dep); got == nil || got.UID != tt.app.UID {
+				t.Errorf("Deployment controller = %v, want App %s", got, tt.app.UID)
+			}
+			if diff := cmp.Diff(tt.app.SelectorLabels(), dep.Spec.Selector.MatchLabels); diff != "" {
+				t.Errorf("selector mismatch (-want +got):\n%s", diff)
+			}
+			container := dep.Spec.Template.Spec.Containers[0]
+			if diff := cmp.Diff(tt.wantEnv, container.Env); diff != "" {
+				t.Errorf("env mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantPorts, container.Ports); diff != "" {
+				t.Errorf("ports mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantAnnotations, dep.Spec.Template.Annotations); diff != "" {
+				t.Errorf("pod annotations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPodInitContainersAndVolumes(t *testing.T) {
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.InitContainers = []corev1.Container{{Name: "migrate", Image: "web:migrate"}}
+		a.Spec.Sidecars = []corev1.Container{{Name: "proxy", Image: "envoy:1.30"}}
+		a.Spec.Volumes = []appv1.Volume{{Name: "cache", EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+		a.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}
+	})
+	dep := (&AppReconciler{Scheme: newTestScheme(t)}).desiredDeployment(app)
+
+	wantInit := []corev1.Container{
+		{Name: "migrate", Image: "web:migrate"},
+		{Name: "proxy", Image: "envoy:1.30", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+	}
+	if diff := cmp.Diff(wantInit, dep.Spec.Template.Spec.InitContainers); diff != "" {
+		t.Errorf("init containers mismatch (-want +got):\n%s", diff)
+	}
+	wantVolumes := []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	if diff := cmp.Diff(wantVolumes, dep.Spec.Template.Spec.Volumes); diff != "" {
+		t.Errorf("volumes mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(app.Spec.VolumeMounts, dep.Spec.Template.Spec.Containers[0].VolumeMounts); diff != "" {
+		t.Errorf("volume mounts mismatch (-want +got):\n%s", diff)
+	}
+
+	// Defaults of the API server are kept, removals are not
+	live := append([]corev1.Container{}, wantInit...)
+	live[0].ImagePullPolicy = corev1.PullIfNotPresent
+	if !derivative(wantInit, live) {
+		t.Error("derivative() = false for defaulted fields")
+	}
+	if derivative(wantInit[:1], live) || derivative(nil, live) {
+		t.Error("derivative() = true for removed containers")
+	}
+}
+
+func TestDesiredService(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   *appv1.ServiceSpec
+		wantType  corev1.ServiceType
+		wantIP    string
+		wantPorts []corev1.ServicePort
+	}{
+		{
+			name:     "defaults",
+			wantType: corev1.ServiceTypeClusterIP,
+			wantPorts: []corev1.ServicePort{{
+				Name: "http", Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt32(8080),
+			}},
+		},
+		{
+			name: "node port",
+			service: &appv1.ServiceSpec{
+				Type:  appv1.ServiceTypeNodePort,
+				Ports: []appv1.ServicePort{{Name: "http", Port: 80, TargetPort: 8080, NodePort: 30080}},
+			},
+			wantType: corev1.ServiceTypeNodePort,
+			wantPorts: []corev1.ServicePort{{
+				Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt32(8080), NodePort: 30080,
+			}},
+		},
+		{
+			name: "cluster IP drops node ports",
+			service: &appv1.ServiceSpec{
+				Type:  appv1.ServiceTypeClusterIP,
+				Ports: []appv1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}},
+			},
+			wantType: corev1.ServiceTypeClusterIP,
+			wantPorts: []corev1.ServicePort{{
+				Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt32(80),
+			}},
+		},
+		{
+			name: "headless",
+			service: &appv1.ServiceSpec{
+				Type:  appv1.ServiceTypeHeadless,
+				Ports: []appv1.ServicePort{{Name: "grpc", Port: 9000, AppProtocol: ptr.To("grpc")}},
+			},
+			wantType: corev1.ServiceTypeClusterIP,
+			wantIP:   corev1.ClusterIPNone,
+			wantPorts: []corev1.ServicePort{{
+				Name: "grpc", Protocol: corev1.ProtocolTCP, AppProtocol: ptr.To("grpc"), Port: 9000, TargetPort: intstr.FromInt32(9000),
+			}},
+		},
+		{
+			name: "load balancer with UDP",
+			service: &appv1.ServiceSpec{
+				Type:  appv1.ServiceTypeLoadBalancer,
+				Ports: []appv1.ServicePort{{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP}},
+			},
+			wantType: corev1.ServiceTypeLoadBalancer,
+			wantPorts: []corev1.ServicePort{{
+				Name: "dns", Protocol: corev1.ProtocolUDP, Port: 53, TargetPort: intstr.FromInt32(53),
+			}},
+		},
+	}
+
+	r := &AppReconciler{Scheme: newTestScheme(t)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(func(app *appv1.App) { app.Spec.Service = tt.service })
+			svc := r.desiredService(app)
+
+			if svc.Name != "web-svc" {
+				t.Errorf("Service name = %s, want web-svc", svc.Name)
+			}
+			if svc.Spec.Type != tt.wantType {
+				t.Errorf("type = %s, want %s", svc.Spec.Type, tt.wantType)
+			}
+			if svc.Spec.ClusterIP != tt.wantIP {
+				t.Errorf("cluster IP = %q, want %q", svc.Spec.ClusterIP, tt.wantIP)
+			}
+			if diff := cmp.Diff(tt.wantPorts, svc.Spec.Ports); diff != "" {
+				t.Errorf("ports mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMutateServiceKeepsAllocatedFields(t *testing.T) {
+	live := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeNodePort,
+			ClusterIP: "10.0.0.10",
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, NodePort: 31000},
+				{Name: "old", Port: 81, NodePort: 31001},
+			},
+		},
+	}
+	desired := &corev1.Service{Spec: corev1.ServiceSpec{
+		Type:      corev1.ServiceTypeNodePort,
+		ClusterIP: "",
+		Ports:     []corev1.ServicePort{{Name: "http", Port: 8080}},
+	}}
+
+	mutateService(live, desired)
+
+	if live.Spec.ClusterIP != "10.0.0.10" {
+		t.Errorf("cluster IP changed to %q", live.Spec.ClusterIP)
+	}
+	want := []corev1.ServicePort{{Name: "http", Port: 8080, NodePort: 31000}}
+	if diff := cmp.Diff(want, live.Spec.Ports); diff != "" {
+		t.Errorf("ports mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMutateServiceAnnotations(t *testing.T) {
+	live := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		CreationTimestamp: metav1.Now(),
+		// Set by another controller
+		Annotations: map[string]string{"cloud.example.com/lb-id": "lb-1"},
+	}}
+	desired := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+		"example.com/team": "shop",
+	}}}
+	mutateService(live, desired)
+	want := map[string]string{
+		"cloud.example.com/lb-id":                               "lb-1",
+		"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+		"example.com/team":                                      "shop",
+		managedAnnotationsAnnotation:                            "example.com/team,service.beta.kubernetes.io/aws-load-balancer-internal",
+	}
+	if diff := cmp.Diff(want, live.Annotations); diff != "" {
+		t.Errorf("annotations mismatch (-want +got):\n%s", diff)
+	}
+
+	// Annotations removed from the App are removed from the Service
+	delete(desired.Annotations, "service.beta.kubernetes.io/aws-load-balancer-internal")
+	mutateService(live, desired)
+	want = map[string]string{
+		"cloud.example.com/lb-id":    "lb-1",
+		"example.com/team":           "shop",
+		managedAnnotationsAnnotation: "example.com/team",
+	}
+	if diff := cmp.Diff(want, live.Annotations); diff != "" {
+		t.Errorf("annotations mismatch (-want +got):\n%s", diff)
+	}
+
+	desired.Annotations = nil
+	mutateService(live, desired)
+	if diff := cmp.Diff(map[string]string{"cloud.example.com/lb-id": "lb-1"}, live.Annotations); diff != "" {
+		t.Errorf("annotations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMutateServiceSessionAffinity(t *testing.T) {
+	// The config defaulted by the API server for ClientIP
+	live := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		Spec: corev1.ServiceSpec{
+			SessionAffinity: corev1.ServiceAffinityClientIP,
+			SessionAffinityConfig: &corev1.SessionAffinityConfig{
+				ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ptr.To[int32](10800)},
+			},
+		},
+	}
+	desired := &corev1.Service{Spec: corev1.ServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP}}
+	mutateService(live, desired)
+	if live.Spec.SessionAffinityConfig == nil {
+		t.Error("sessionAffinityConfig cleared with ClientIP")
+	}
+
+	desired.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	mutateService(live, desired)
+	if live.Spec.SessionAffinity != corev1.ServiceAffinityNone || live.Spec.SessionAffinityConfig != nil {
+		t.Errorf("session affinity = %s %+v, want None without config", live.Spec.SessionAffinity, live.Spec.SessionAffinityConfig)
+	}
+}
+
+func TestDesiredIngress(t *testing.T) {
+	r := &AppReconciler{Scheme: newTestScheme(t)}
+	if ing := r.desiredIngress(newTestApp(nil)); ing != nil {
+		t.Fatalf("desiredIngress() = %v without spec.ingress, want nil", ing)
+	}
+
+	ing := r.desiredIngress(newTestApp(func(a *appv1.App) {
+		a.Spec.Ingress = &appv1.IngressSpec{Host: "web.example.com", ClassName: ptr.To("traefik")}
+	}))
+	if ing.Name != "web-ingress" || *ing.Spec.IngressClassName != "traefik" {
+		t.Errorf("name = %s, class = %s", ing.Name, *ing.Spec.IngressClassName)
+	}
+	rule := ing.Spec.Rules[0]
+	path := rule.HTTP.Paths[0]
+	if rule.Host != "web.example.com" || path.Path != "/" {
+		t.Errorf("host = %s, path = %s", rule.Host, path.Path)
+	}
+	if b := path.Backend.Service; b.Name != "web-svc" || b.Port.Name != "http" {
+		t.Errorf("backend = %s:%s, want web-svc:http", b.Name, b.Port.Name)
+	}
+}
+
+func TestReconcileWithFakeClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		app      *appv1.App
+		existing []client.Object
+		wantErr  bool
+	}{
+		{name: "creates owned objects", app: newTestApp(nil)},
+		{
+			name: "updates drifted Deployment",
+			app:  newTestApp(nil),
+			existing: []client.Object{&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-app", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To[int32](5),
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "nginx:old"}},
+					}},
+				},
+			}},
+		},
+		{
+			name: "fails on a Deployment controlled by another object",
+			app:  newTestApp(nil),
+			existing: []client.Object{&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "web-app", Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid", Controller: ptr.To(true),
+					}},
+				},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}},
+				}}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := newTestScheme(t)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(append(tt.existing, tt.app)...).
+				WithStatusSubresource(&appv1.App{}).
+				Build()
+			r := &AppReconciler{Client: c, Scheme: scheme}
+
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(tt.app)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			dep := &appsv1.Deployment{}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+				t.Fatal(err)
+			}
+			if got := *dep.Spec.Replicas; got != 2 {
+				t.Errorf("replicas = %d, want 2", got)
+			}
+			if got := dep.Spec.Template.Spec.Containers[0].Image; got != "nginx:1.27" {
+				t.Errorf("image = %s, want nginx:1.27", got)
+			}
+			if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-svc"}, &corev1.Service{}); err != nil {
+				t.Errorf("Service not created: %v", err)
+			}
+			app := &appv1.App{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(tt.app), app); err != nil {
+				t.Fatal(err)
+			}
+			if app.Status.Phase != "Running" {
+				t.Errorf("phase = %q, want Running", app.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestReconcileInvalidSpec(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(&appv1.App{}).Build()
+	r := &AppReconciler{Client: c, Scheme: scheme}
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, &appsv1.Deployment{}); err == nil {
+		t.Error("Deployment created for an invalid spec")
+	}
+	assertCondition(t, c, app, appv1.ConditionSpecValid, "InvalidSpec")
+}
+
+func TestPauseAndMaintenance(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) { a.Spec.Paused = true })
+	drifted := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-app", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:debug"}},
+		}}},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app, drifted).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{Client: c, Scheme: scheme}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	// Paused: the Deployment edited by hand is left untouched
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(drifted), dep); err != nil {
+		t.Fatal(err)
+	}
+	if got := dep.Spec.Template.Spec.Containers[0].Image; got != "nginx:debug" {
+		t.Errorf("image = %s, want the paused nginx:debug", got)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-svc"}, &corev1.Service{}); err == nil {
+		t.Error("Service created while paused")
+	}
+	assertCondition(t, c, app, appv1.ConditionPaused, "PausedBySpec")
+
+	// Maintenance: the Service selects the maintenance backend
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Paused = false
+	app.Spec.Maintenance = &appv1.MaintenanceSpec{Enabled: true, Selector: map[string]string{"app": "maintenance-page"}}
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	svc := &corev1.Service{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-svc"}, svc); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"app": "maintenance-page"}, svc.Spec.Selector); diff != "" {
+		t.Errorf("selector mismatch (-want +got):\n%s", diff)
+	}
+	assertCondition(t, c, app, appv1.ConditionPaused, "Reconciling")
+	assertCondition(t, c, app, appv1.ConditionMaintenance, "MaintenanceEnabled")
+
+	// Back to the app
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	if app.Status.Phase != "Maintenance" {
+		t.Errorf("phase = %q, want Maintenance", app.Status.Phase)
+	}
+	app.Spec.Maintenance.Enabled = false
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(svc), svc); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(app.SelectorLabels(), svc.Spec.Selector); diff != "" {
+		t.Errorf("selector mismatch (-want +got):\n%s", diff)
+	}
+	assertCondition(t, c, app, appv1.ConditionMaintenance, "Serving")
+}
+
+// assertCondition checks the reason of a condition of the stored App
+func assertCondition(t *testing.T, c client.Client, app *appv1.App, condType, reason string) {
+	t.Helper()
+	got := &appv1.App{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(app), got); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, condType)
+	if cond == nil {
+		t.Fatalf("condition %s not set", condType)
+	}
+	if cond.Reason != reason {
+		t.Errorf("condition %s reason = %s (%s), want %s", condType, cond.Reason, cond.Message, reason)
+	}
+}
+
+func TestShardOwns(t *testing.T) {
+	shards := []Shard{{Count: 3, ID: 0}, {Count: 3, ID: 1}, {Count: 3, ID: 2}}
+	for i := 0; i < 100; i++ {
+		app := newTestApp(func(app *appv1.App) { app.Name = fmt.Sprintf("app-%d", i) })
+		owners := 0
+		for _, s := range shards {
+			if s.Owns(app) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("app %s owned by %d shards, want 1", app.Name, owners)
+		}
+	}
+
+	labelled := Shard{Count: 3, Label: "team"}
+	a := newTestApp(func(app *appv1.App) { app.Name, app.Labels = "a", map[string]string{"team": "payments"} })
+	b := newTestApp(func(app *appv1.App) { app.Name, app.Labels = "b", map[string]string{"team": "payments"} })
+	for id := 0; id < 3; id++ {
+		labelled.ID = id
+		if labelled.Owns(a) != labelled.Owns(b) {
+			t.Errorf("Apps with the same shard label are split across shards")
+		}
+	}
+
+	if !(Shard{}).Owns(a) {
+		t.Errorf("the zero Shard must own every App")
+	}
+}
@@ -0,0 +1,407 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// previewAppNameField indexes AppPreviews by the App they clone
+const previewAppNameField = "spec.appName"
+
+// defaultPreviewTTL matches the default of AppPreviewSpec.TTL
+const defaultPreviewTTL = 72 * time.Hour
+
+// AppPreviewReconciler reconciles a AppPreview object
+type AppPreviewReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Clock is used to expire previews. Defaults to the real clock.
+	Clock clock.PassiveClock
+
+	// DrainTimeout is how long the reconciles in flight may run on after the
+	// manager is stopped, less than the GracefulShutdownTimeout of the manager.
+	// Zero cancels them with the manager.
+	DrainTimeout time.Duration
+}
+
+// +kubebuilder:rbac:groups=apps.test.local,resources=apppreviews,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.test.local,resources=apppreviews/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.test.local,resources=apppreviews/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile clones the App of an AppPreview into the namespace of the preview,
+// and deletes the preview with its namespace once its TTL has expired.
+func (r *AppPreviewReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	preview := &appv1.AppPreview{}
+	if err := r.Get(ctx, req.NamespacedName, preview); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !preview.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, preview)
+	}
+	if controllerutil.AddFinalizer(preview, appv1.PreviewFinalizer) {
+		if err := r.Update(ctx, preview); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Garbage-collect the preview once expired
+	expiresAt := previewExpiresAt(preview)
+	if remaining := expiresAt.Sub(r.now()); remaining <= 0 {
+		log.Info("AppPreview expired", "expiresAt", expiresAt)
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, preview))
+	}
+
+	status := preview.Status.DeepCopy()
+	status.ExpiresAt = &metav1.Time{Time: expiresAt}
+	status.Namespace = previewNamespace(preview)
+
+	src := &appv1.App{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: preview.Namespace, Name: preview.Spec.AppName}, src)
+	if apierrors.IsNotFound(err) {
+		status.Phase = "Pending"
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               appv1.ConditionPreviewReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "AppNotFound",
+			Message:            fmt.Sprintf("App %s not found", preview.Spec.AppName),
+			ObservedGeneration: preview.Generation,
+		})
+		return r.requeueAt(expiresAt), r.updateStatus(ctx, preview, status)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 1. Reconcile the namespace of the preview
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: status.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, ns, func() error {
+		if ns.Labels[appv1.PreviewNamespaceLabel] != "" &&
+			(ns.Labels[appv1.PreviewNamespaceLabel] != preview.Namespace || ns.Labels[appv1.PreviewNameLabel] != preview.Name) {
+			return fmt.Errorf("namespace %s belongs to another preview", ns.Name)
+		}
+		metav1.SetMetaDataLabel(&ns.ObjectMeta, appv1.PreviewNameLabel, preview.Name)
+		metav1.SetMetaDataLabel(&ns.ObjectMeta, appv1.PreviewNamespaceLabel, preview.Namespace)
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile preview Namespace")
+		return ctrl.Result{}, err
+	}
+	log.Info("Namespace reconciled", "operation", op, "name", ns.Name)
+
+	// 2. Reconcile the clone of the App
+	clone := desiredPreviewApp(preview, src)
+	desired := clone.DeepCopy()
+	op, err = controllerutil.CreateOrUpdate(ctx, r.Client, clone, func() error {
+		for k, v := range desired.Labels {
+			metav1.SetMetaDataLabel(&clone.ObjectMeta, k, v)
+		}
+		clone.Spec = desired.Spec
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to reconcile preview App")
+		return ctrl.Result{}, err
+	}
+	log.Info("App reconciled", "operation", op, "name", clone.Name, "namespace", clone.Namespace)
+
+	// 3. Update status
+	status.Host, status.URL = "", ""
+	if clone.Spec.Ingress != nil {
+		status.Host = clone.Spec.Ingress.Host
+		status.URL = "http://" + status.Host
+	}
+	replicas := int32(1)
+	if clone.Spec.Replicas != nil {
+		replicas = *clone.Spec.Replicas
+	}
+	ready := metav1.Condition{
+		Type:               appv1.ConditionPreviewReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "AppAvailable",
+		Message:            fmt.Sprintf("%d/%d replicas ready", clone.Status.ReadyReplicas, replicas),
+		ObservedGeneration: preview.Generation,
+	}
+	status.Phase = "Ready"
+	if clone.Status.ReadyReplicas < replicas {
+		ready.Status, ready.Reason = metav1.ConditionFalse, "AppProgressing"
+		status.Phase = "Pending"
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	references := metav1.Condition{
+		Type:               appv1.ConditionPreviewReferencesResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NoLocalReferences",
+		Message:            fmt.Sprintf("App %s references no object of namespace %s", src.Name, src.Namespace),
+		ObservedGeneration: preview.Generation,
+	}
+	if unresolved := previewReferences(src); len(unresolved) > 0 {
+		references.Status, references.Reason = metav1.ConditionFalse, "UnresolvedReferences"
+		references.Message = fmt.Sprintf("Not copied from namespace %s: %s", src.Namespace, strings.Join(unresolved, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, references)
+
+	return r.requeueAt(expiresAt), r.updateStatus(ctx, preview, status)
+}
+
+// finalize deletes the namespace of the preview, then releases the AppPreview
+func (r *AppPreviewReconciler) finalize(ctx context.Context, preview *appv1.AppPreview) error {
+	if !controllerutil.ContainsFinalizer(preview, appv1.PreviewFinalizer) {
+		return nil
+	}
+	ns := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: previewNamespace(preview)}, ns)
+	if err == nil && ns.Labels[appv1.PreviewNamespaceLabel] == preview.Namespace &&
+		ns.Labels[appv1.PreviewNameLabel] == preview.Name {
+		if err := r.Delete(ctx, ns); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Namespace deleted", "name", ns.Name)
+	} else if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	controllerutil.RemoveFinalizer(preview, appv1.PreviewFinalizer)
+	return r.Update(ctx, preview)
+}
+
+func (r *AppPreviewReconciler) updateStatus(ctx context.Context, preview *appv1.AppPreview, status *appv1.AppPreviewStatus) error {
+	if equality.Semantic.DeepEqual(&preview.Status, status) {
+		return nil
+	}
+	preview.Status = *status
+	return r.Status().Update(ctx, preview)
+}
+
+// requeueAt reconciles the preview again when it expires
+func (r *AppPreviewReconciler) requeueAt(t time.Time) ctrl.Result {
+	return ctrl.Result{RequeueAfter: t.Sub(r.now())}
+}
+
+func (r *AppPreviewReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// previewExpiresAt returns the creation time of the preview plus its TTL
+func previewExpiresAt(preview *appv1.AppPreview) time.Time {
+	ttl := defaultPreviewTTL
+	if preview.Spec.TTL != nil {
+		ttl = preview.Spec.TTL.Duration
+	}
+	return preview.CreationTimestamp.Add(ttl)
+}
+
+// previewNamespace returns the namespace of a preview: preview-<name>-<hash>,
+// the hash telling apart previews of the same name in different namespaces
+func previewNamespace(preview *appv1.AppPreview) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(preview.Namespace + "/" + preview.Name))
+	name := preview.Name
+	if len(name) > 45 {
+		name = strings.TrimRight(name[:45], "-.")
+	}
+	return fmt.Sprintf("preview-%s-%08x", name, h.Sum32())
+}
+
+// previewHost returns <app>-<preview>.<domain>, or an empty string when
+// neither the preview nor the App sets a domain. A first label over the 63
+// characters of DNS is truncated and suffixed with its hash, unique per preview.
+func previewHost(preview *appv1.AppPreview, src *appv1.App) string {
+	domain := preview.Spec.Domain
+	if domain == "" && src.Spec.Ingress != nil {
+		if _, parent, ok := strings.Cut(src.Spec.Ingress.Host, "."); ok {
+			domain = parent
+		}
+	}
+	if domain == "" {
+		return ""
+	}
+	label := src.Name + "-" + preview.Name
+	if len(label) > validation.DNS1123LabelMaxLength {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(label))
+		label = fmt.Sprintf("%s-%08x", strings.TrimRight(label[:validation.DNS1123LabelMaxLength-9], "-"), h.Sum32())
+	}
+	return label + "." + domain
+}
+
+// desiredPreviewApp returns the clone of src deployed by the preview
+func desiredPreviewApp(preview *appv1.AppPreview, src *appv1.App) *appv1.App {
+	clone := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      src.Name,
+			Namespace: previewNamespace(preview),
+			Labels: map[string]string{
+				appv1.PreviewNameLabel:      preview.Name,
+				appv1.PreviewNamespaceLabel: preview.Namespace,
+			},
+		},
+		Spec: *src.Spec.DeepCopy(),
+	}
+	clone.Spec.Image = preview.Spec.Image
+
+	if host := previewHost(preview, src); host != "" {
+		if clone.Spec.Ingress == nil {
+			clone.Spec.Ingress = &appv1.IngressSpec{}
+		}
+		clone.Spec.Ingress.Host = host
+	} else {
+		clone.Spec.Ingress = nil
+	}
+	return clone
+}
+
+// previewReferences returns the objects of the namespace of the App that its
+// spec references, e.g. "Secret db", sorted. The clone refers to them in the
+// namespace of the preview, where they are not copied: Secrets and ConfigMaps
+// may hold credentials of the source environment.
+func previewReferences(src *appv1.App) []string {
+	refs := sets.New[string]()
+	envRefs := func(env []corev1.EnvVar, envFrom []corev1.EnvFromSource) {
+		for _, e := range env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+				refs.Insert("Secret " + ref.Name)
+			}
+			if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs.Insert("ConfigMap " + ref.Name)
+			}
+		}
+		for _, e := range envFrom {
+			if e.SecretRef != nil {
+				refs.Insert("Secret " + e.SecretRef.Name)
+			}
+			if e.ConfigMapRef != nil {
+				refs.Insert("ConfigMap " + e.ConfigMapRef.Name)
+			}
+		}
+	}
+	envRefs(src.Spec.Env, nil)
+	for _, containers := range [][]corev1.Container{src.Spec.InitContainers, src.Spec.Sidecars} {
+		for _, c := range containers {
+			envRefs(c.Env, c.EnvFrom)
+		}
+	}
+
+	for _, v := range src.Spec.Volumes {
+		switch {
+		case v.ConfigMap != nil:
+			refs.Insert("ConfigMap " + v.ConfigMap.Name)
+		case v.Secret != nil:
+			refs.Insert("Secret " + v.Secret.SecretName)
+		case v.PersistentVolumeClaim != nil:
+			refs.Insert("PersistentVolumeClaim " + v.PersistentVolumeClaim.ClaimName)
+		case v.Projected != nil:
+			for _, s := range v.Projected.Sources {
+				if s.ConfigMap != nil {
+					refs.Insert("ConfigMap " + s.ConfigMap.Name)
+				}
+				if s.Secret != nil {
+					refs.Insert("Secret " + s.Secret.Name)
+				}
+			}
+		}
+	}
+
+	// The vault documents are read per namespace, and the Secrets of the
+	// namespace of the App are only read by the preview when shared with it
+	for _, s := range src.Spec.SecretsFrom {
+		switch s.Provider {
+		case appv1.SecretProviderVault:
+			refs.Insert("Vault document " + s.Path)
+		case appv1.SecretProviderSecret:
+			if ns, name, _ := strings.Cut(s.Path, "/"); ns == src.Namespace {
+				refs.Insert("Secret " + name)
+			}
+		}
+	}
+
+	if tls := src.Spec.TLS; tls != nil && tls.IssuerRef.Kind != "ClusterIssuer" {
+		refs.Insert("Issuer " + tls.IssuerRef.Name)
+	}
+	return sets.List(refs)
+}
+
+// previewsForApp maps an App to the AppPreviews cloning it, and a cloned App
+// to its AppPreview
+func (r *AppPreviewReconciler) previewsForApp(ctx context.Context, obj client.Object) []reconcile.Request {
+	if name, ns := obj.GetLabels()[appv1.PreviewNameLabel], obj.GetLabels()[appv1.PreviewNamespaceLabel]; name != "" && ns != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: ns, Name: name}}}
+	}
+
+	previews := &appv1.AppPreviewList{}
+	if err := r.List(ctx, previews, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{previewAppNameField: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list AppPreviews", "app", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(previews.Items))
+	for _, p := range previews.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&p)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AppPreviewReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &appv1.AppPreview{}, previewAppNameField,
+		func(obj client.Object) []string {
+			return []string{obj.(*appv1.AppPreview).Spec.AppName}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appv1.AppPreview{}).
+		Watches(&appv1.App{}, handler.EnqueueRequestsFromMapFunc(r.previewsForApp)).
+		Complete(drain(r, r.DrainTimeout))
+}
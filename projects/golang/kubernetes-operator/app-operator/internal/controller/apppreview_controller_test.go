@@ -0,0 +1,215 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func newTestPreview(mutate func(p *appv1.AppPreview)) *appv1.AppPreview {
+	p := &appv1.AppPreview{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pr-42", Namespace: "default",
+			CreationTimestamp: metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		Spec: appv1.AppPreviewSpec{
+			AppName: "web",
+			Image:   "nginx:pr-42",
+			TTL:     &metav1.Duration{Duration: time.Hour},
+		},
+	}
+	if mutate != nil {
+		mutate(p)
+	}
+	return p
+}
+
+func TestPreviewHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		preview *appv1.AppPreview
+		app     *appv1.App
+		want    string
+	}{
+		{
+			name:    "domain of the preview",
+			preview: newTestPreview(func(p *appv1.AppPreview) { p.Spec.Domain = "preview.example.com" }),
+			app:     newTestApp(nil),
+			want:    "web-pr-42.preview.example.com",
+		},
+		{
+			name:    "parent domain of the App ingress",
+			preview: newTestPreview(nil),
+			app:     newTestApp(func(a *appv1.App) { a.Spec.Ingress = &appv1.IngressSpec{Host: "web.example.com"} }),
+			want:    "web-pr-42.example.com",
+		},
+		{name: "no domain", preview: newTestPreview(nil), app: newTestApp(nil)},
+		{
+			name: "label over 63 characters",
+			preview: newTestPreview(func(p *appv1.AppPreview) {
+				p.Name = "pr-42-" + strings.Repeat("feature", 8)
+				p.Spec.Domain = "preview.example.com"
+			}),
+			app:  newTestApp(nil),
+			want: "web-pr-42-featurefeaturefeaturefeaturefeaturefeaturefe-b11e7d9a.preview.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := previewHost(tt.preview, tt.app); got != tt.want {
+				t.Errorf("previewHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviewReferences(t *testing.T) {
+	src := newTestApp(func(a *appv1.App) {
+		a.Spec.Env = []corev1.EnvVar{
+			{Name: "LEVEL", Value: "debug"},
+			{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+			}},
+		}
+		a.Spec.Sidecars = []corev1.Container{{
+			Name: "proxy", Image: "envoy:1.30",
+			EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "proxy"}}}},
+		}}
+		a.Spec.Volumes = []appv1.Volume{
+			{Name: "config", ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+			{Name: "certs", Secret: &corev1.SecretVolumeSource{SecretName: "db"}},
+			{Name: "scratch", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}
+		a.Spec.SecretsFrom = []appv1.SecretSource{
+			{Provider: appv1.SecretProviderVault, Path: "web/api"},
+			{Provider: appv1.SecretProviderSecret, Path: "default/api-token"},
+			{Provider: appv1.SecretProviderSecret, Path: "shared/registry"},
+		}
+		a.Spec.TLS = &appv1.TLSSpec{IssuerRef: appv1.IssuerReference{Name: "ca", Kind: "Issuer"}}
+	})
+	want := []string{"ConfigMap proxy", "ConfigMap settings", "Issuer ca", "Secret api-token", "Secret db", "Vault document web/api"}
+	if got := previewReferences(src); !slices.Equal(got, want) {
+		t.Errorf("previewReferences() = %v, want %v", got, want)
+	}
+
+	src.Spec.TLS.IssuerRef.Kind = "ClusterIssuer"
+	src.Spec.Env, src.Spec.Sidecars, src.Spec.Volumes, src.Spec.SecretsFrom = nil, nil, nil, nil
+	if got := previewReferences(src); len(got) != 0 {
+		t.Errorf("previewReferences() = %v, want none", got)
+	}
+}
+
+func TestAppPreviewReconcile(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	src := newTestApp(func(a *appv1.App) {
+		a.Spec.Ingress = &appv1.IngressSpec{Host: "web.example.com", Port: "http"}
+	})
+	preview := newTestPreview(nil)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(src, preview).
+		WithStatusSubresource(&appv1.App{}, &appv1.AppPreview{}).
+		WithIndex(&appv1.AppPreview{}, previewAppNameField, func(obj client.Object) []string {
+			return []string{obj.(*appv1.AppPreview).Spec.AppName}
+		}).
+		Build()
+	clock := clocktesting.NewFakePassiveClock(preview.CreationTimestamp.Add(10 * time.Minute))
+	r := &AppPreviewReconciler{Client: c, Scheme: scheme, Clock: clock}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(preview)}
+
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != 50*time.Minute {
+		t.Errorf("RequeueAfter = %s, want 50m", res.RequeueAfter)
+	}
+
+	nsName := previewNamespace(preview)
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nsName}, ns); err != nil {
+		t.Fatalf("preview namespace not created: %v", err)
+	}
+	clone := &appv1.App{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: nsName, Name: "web"}, clone); err != nil {
+		t.Fatalf("App not cloned: %v", err)
+	}
+	if clone.Spec.Image != "nginx:pr-42" {
+		t.Errorf("clone image = %s, want nginx:pr-42", clone.Spec.Image)
+	}
+	if clone.Spec.Ingress == nil || clone.Spec.Ingress.Host != "web-pr-42.example.com" || clone.Spec.Ingress.Port != "http" {
+		t.Errorf("clone ingress = %+v", clone.Spec.Ingress)
+	}
+
+	if err := c.Get(ctx, req.NamespacedName, preview); err != nil {
+		t.Fatal(err)
+	}
+	if preview.Status.Namespace != nsName || preview.Status.URL != "http://web-pr-42.example.com" {
+		t.Errorf("status = %+v", preview.Status)
+	}
+	if cond := meta.FindStatusCondition(preview.Status.Conditions, appv1.ConditionPreviewReferencesResolved); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("ReferencesResolved condition = %+v, want True", cond)
+	}
+	if preview.Status.Phase != "Pending" {
+		t.Errorf("phase = %q, want Pending until the clone is ready", preview.Status.Phase)
+	}
+	if got := previewsForAppNames(r.previewsForApp(ctx, src)); got != "default/pr-42" {
+		t.Errorf("previewsForApp(source) = %s", got)
+	}
+	if got := previewsForAppNames(r.previewsForApp(ctx, clone)); got != "default/pr-42" {
+		t.Errorf("previewsForApp(clone) = %s", got)
+	}
+
+	// Expired: the preview deletes itself, then its finalizer deletes the namespace
+	clock.SetTime(preview.CreationTimestamp.Add(2 * time.Hour))
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, preview); !apierrors.IsNotFound(err) {
+		t.Errorf("expired preview not deleted: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: nsName}, ns); !apierrors.IsNotFound(err) {
+		t.Errorf("preview namespace not deleted: %v", err)
+	}
+}
+
+func previewsForAppNames(requests []reconcile.Request) string {
+	names := ""
+	for _, req := range requests {
+		names += req.String()
+	}
+	return names
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// AppSetReconciler reconciles a AppSet object
+type AppSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// DrainTimeout is how long the reconciles in flight may run on after the
+	// manager is stopped, less than the GracefulShutdownTimeout of the manager.
+	// Zero cancels them with the manager.
+	DrainTimeout time.Duration
+}
+
+// appSetTarget is a target namespace with its App, nil until generated
+type appSetTarget struct {
+	namespace string
+	app       *appv1.App
+	desired   *appv1.App
+	status    appv1.AppSetTarget
+}
+
+// +kubebuilder:rbac:groups=apps.test.local,resources=appsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.test.local,resources=appsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.test.local,resources=appsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile generates the App of an AppSet in each target namespace. New targets
+// are created right away, while outdated Apps are updated in namespace order as
+// long as no more than maxUnavailable targets are unavailable.
+func (r *AppSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	set := &appv1.AppSet{}
+	if err := r.Get(ctx, req.NamespacedName, set); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// The CRD rejects them, unless the AppSet predates the rule. The Apps are
+	// written by the operator, whose permissions pass the review of the rules.
+	if sa := set.Spec.Template.Spec.ServiceAccount; sa != nil && len(sa.Rules) > 0 {
+		log.Info("AppSet template sets spec.serviceAccount.rules, no App is generated")
+		return ctrl.Result{}, r.rejectTemplate(ctx, set, "spec.serviceAccount.rules cannot be set in an AppSet template")
+	}
+
+	namespaces, err := r.targetNamespaces(ctx, set)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	owned := &appv1.AppList{}
+	if err := r.List(ctx, owned, client.MatchingLabels{appv1.AppSetNameLabel: set.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 1. Match the targets with their Apps and delete the Apps of former targets
+	targets := make([]*appSetTarget, 0, len(namespaces))
+	for _, ns := range namespaces {
+		targets = append(targets, &appSetTarget{
+			namespace: ns,
+			desired:   desiredSetApp(set, ns),
+			status:    appv1.AppSetTarget{Namespace: ns},
+		})
+	}
+	for i := range owned.Items {
+		app := &owned.Items[i]
+		if !metav1.IsControlledBy(app, set) {
+			continue
+		}
+		idx := slices.IndexFunc(targets, func(t *appSetTarget) bool { return t.namespace == app.Namespace })
+		if idx >= 0 {
+			targets[idx].app = app
+			continue
+		}
+		if err := r.Delete(ctx, app); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Failed to delete App", "namespace", app.Namespace)
+			return ctrl.Result{}, err
+		}
+		log.Info("App deleted", "namespace", app.Namespace)
+	}
+
+	// 2. Create the Apps of new targets
+	var outdated []*appSetTarget
+	unavailable := 0
+	for _, t := range targets {
+		if t.app == nil {
+			if err := r.createSetApp(ctx, set, t); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		t.status.Updated = t.app.Annotations[appv1.AppSetTemplateHashAnnotation] == t.desired.Annotations[appv1.AppSetTemplateHashAnnotation]
+		t.status.Available = appAvailable(t.app)
+		if !t.status.Available {
+			unavailable++
+		}
+		if !t.status.Updated {
+			outdated = append(outdated, t)
+		}
+	}
+
+	// 3. Roll the template out to outdated Apps: unavailable ones first since
+	// updating them adds no disruption, then within the budget
+	maxUnavailable := appSetMaxUnavailable(set, len(targets))
+	slices.SortStableFunc(outdated, func(a, b *appSetTarget) int {
+		if a.status.Available == b.status.Available {
+			return 0
+		}
+		if a.status.Available {
+			return 1
+		}
+		return -1
+	})
+	for _, t := range outdated {
+		if t.status.Available {
+			if unavailable >= maxUnavailable {
+				break
+			}
+			unavailable++
+		}
+		if err := r.updateSetApp(ctx, set, t); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// 4. Update status
+	return ctrl.Result{}, r.updateStatus(ctx, set, targets)
+}
+
+// targetNamespaces returns the sorted names of the namespaces listed by the
+// AppSet or matching its selector, skipping the namespaces being deleted
+func (r *AppSetReconciler) targetNamespaces(ctx context.Context, set *appv1.AppSet) ([]string, error) {
+	selector := labels.Nothing()
+	if set.Spec.NamespaceSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(set.Spec.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, ns := range namespaces.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating || !ns.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if slices.Contains(set.Spec.Namespaces, ns.Name) || selector.Matches(labels.Set(ns.Labels)) {
+			names = append(names, ns.Name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// createSetApp creates the App of a new target. An App of the same name that
+// the AppSet does not control is left alone and reported in the target status.
+func (r *AppSetReconciler) createSetApp(ctx context.Context, set *appv1.AppSet, t *appSetTarget) error {
+	app := t.desired.DeepCopy()
+	if err := ctrl.SetControllerReference(set, app, r.Scheme); err != nil {
+		return err
+	}
+	err := r.Create(ctx, app)
+	if apierrors.IsAlreadyExists(err) {
+		t.status.Message = fmt.Sprintf("App %s already exists and is not generated by the AppSet", app.Name)
+		return nil
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to create App", "namespace", t.namespace)
+		return err
+	}
+	log.FromContext(ctx).Info("App created", "namespace", t.namespace)
+	t.app = app
+	t.status.Updated = true
+	return nil
+}
+
+// updateSetApp rolls the current template out to the App of a target
+func (r *AppSetReconciler) updateSetApp(ctx context.Context, set *appv1.AppSet, t *appSetTarget) error {
+	app := t.app
+	for k, v := range t.desired.Labels {
+		metav1.SetMetaDataLabel(&app.ObjectMeta, k, v)
+	}
+	for k, v := range t.desired.Annotations {
+		metav1.SetMetaDataAnnotation(&app.ObjectMeta, k, v)
+	}
+	app.Spec = t.desired.Spec
+	if err := ctrl.SetControllerReference(set, app, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, app); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update App", "namespace", t.namespace)
+		return err
+	}
+	log.FromContext(ctx).Info("App updated", "namespace", t.namespace)
+	t.status.Updated = true
+	t.status.Available = false
+	return nil
+}
+
+func (r *AppSetReconciler) updateStatus(ctx context.Context, set *appv1.AppSet, targets []*appSetTarget) error {
+	status := set.Status.DeepCopy()
+	status.ObservedGeneration = set.Generation
+	status.Targets = int32(len(targets))
+	status.UpdatedTargets, status.AvailableTargets = 0, 0
+	status.TargetStatuses = make([]appv1.AppSetTarget, 0, len(targets))
+
+	var pending, conflicts []string
+	for _, t := range targets {
+		status.TargetStatuses = append(status.TargetStatuses, t.status)
+		switch {
+		case t.status.Message != "":
+			conflicts = append(conflicts, t.namespace)
+		case !t.status.Updated || !t.status.Available:
+			pending = append(pending, t.namespace)
+		}
+		if t.status.Updated {
+			status.UpdatedTargets++
+		}
+		if t.status.Available {
+			status.AvailableTargets++
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:               appv1.ConditionAppSetReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RolloutComplete",
+		Message:            fmt.Sprintf("%d targets updated and available", len(targets)),
+		ObservedGeneration: set.Generation,
+	}
+	status.Phase = "Ready"
+	switch {
+	case len(conflicts) > 0:
+		ready.Status, ready.Reason = metav1.ConditionFalse, "AppConflict"
+		ready.Message = "Apps not generated by the AppSet in " + strings.Join(conflicts, ", ")
+		status.Phase = "Failed"
+	case len(pending) > 0:
+		ready.Status, ready.Reason = metav1.ConditionFalse, "Progressing"
+		ready.Message = fmt.Sprintf("%d/%d targets pending: %s", len(pending), len(targets), summarizeNames(pending, 5))
+		status.Phase = "Progressing"
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	if equality.Semantic.DeepEqual(&set.Status, status) {
+		return nil
+	}
+	set.Status = *status
+	return r.Status().Update(ctx, set)
+}
+
+// rejectTemplate reports a template the AppSet does not generate Apps from
+func (r *AppSetReconciler) rejectTemplate(ctx context.Context, set *appv1.AppSet, message string) error {
+	status := set.Status.DeepCopy()
+	status.ObservedGeneration = set.Generation
+	status.Phase = "Failed"
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               appv1.ConditionAppSetReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "InvalidTemplate",
+		Message:            message,
+		ObservedGeneration: set.Generation,
+	})
+	if equality.Semantic.DeepEqual(&set.Status, status) {
+		return nil
+	}
+	set.Status = *status
+	return r.Status().Update(ctx, set)
+}
+
+// summarizeNames joins the first n names, followed by the count of the others
+func summarizeNames(names []string, n int) string {
+	if len(names) <= n {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:n], ", "), len(names)-n)
+}
+
+// appAvailable reports whether an App has reconciled its current generation
+// and all its replicas run the current pod template and are ready
+func appAvailable(app *appv1.App) bool {
+	if app.Status.ObservedGeneration != app.Generation || app.Status.Phase == "Failed" {
+		return false
+	}
+	replicas := int32(1)
+	if app.Spec.Replicas != nil {
+		replicas = *app.Spec.Replicas
+	}
+	return app.Status.ReadyReplicas >= replicas && app.Status.UpdatedReplicas >= replicas
+}
+
+// appSetMaxUnavailable resolves strategy.maxUnavailable against the number of
+// targets, rounding down but allowing at least one App to be updated at a time
+func appSetMaxUnavailable(set *appv1.AppSet, targets int) int {
+	maxUnavailable := intstr.FromInt32(1)
+	if set.Spec.Strategy.MaxUnavailable != nil {
+		maxUnavailable = *set.Spec.Strategy.MaxUnavailable
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, targets, false)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// desiredSetApp returns the App generated by the AppSet in a namespace, its
+// template hash annotation covering the overrides of the namespace
+func desiredSetApp(set *appv1.AppSet, namespace string) *appv1.App {
+	tmpl := set.Spec.Template.DeepCopy()
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        set.Name,
+			Namespace:   namespace,
+			Labels:      tmpl.Metadata.Labels,
+			Annotations: tmpl.Metadata.Annotations,
+		},
+		Spec: tmpl.Spec,
+	}
+	if i := slices.IndexFunc(set.Spec.Overrides, func(o appv1.AppSetOverride) bool { return o.Namespace == namespace }); i >= 0 {
+		applyAppSetOverride(&app.Spec, set.Spec.Overrides[i].DeepCopy())
+	}
+
+	h := fnv.New32a()
+	_ = json.NewEncoder(h).Encode(struct {
+		Metadata appv1.AppTemplateMetadata
+		Spec     appv1.AppSpec
+	}{tmpl.Metadata, app.Spec})
+	metav1.SetMetaDataLabel(&app.ObjectMeta, appv1.AppSetNameLabel, set.Name)
+	metav1.SetMetaDataAnnotation(&app.ObjectMeta, appv1.AppSetTemplateHashAnnotation, fmt.Sprintf("%08x", h.Sum32()))
+	return app
+}
+
+// applyAppSetOverride replaces the fields of spec set by the override
+func applyAppSetOverride(spec *appv1.AppSpec, o *appv1.AppSetOverride) {
+	if o.Image != "" {
+		spec.Image = o.Image
+	}
+	if o.Replicas != nil {
+		spec.Replicas = o.Replicas
+	}
+	for _, env := range o.Env {
+		if i := slices.IndexFunc(spec.Env, func(e corev1.EnvVar) bool { return e.Name == env.Name }); i >= 0 {
+			spec.Env[i] = env
+		} else {
+			spec.Env = append(spec.Env, env)
+		}
+	}
+	if o.Resources != nil {
+		spec.Resources = o.Resources
+	}
+	if o.Ingress != nil {
+		spec.Ingress = o.Ingress
+	}
+}
+
+// appSetsForNamespace maps a Namespace to the AppSets, since any of them may
+// select it
+func (r *AppSetReconciler) appSetsForNamespace(ctx context.Context, _ client.Object) []reconcile.Request {
+	sets := &appv1.AppSetList{}
+	if err := r.List(ctx, sets); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list AppSets")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(sets.Items))
+	for _, s := range sets.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&s)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AppSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appv1.AppSet{}).
+		Owns(&appv1.App{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.appSetsForNamespace)).
+		Complete(drain(r, r.DrainTimeout))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestAppSetMaxUnavailable(t *testing.T) {
+	tests := []struct {
+		maxUnavailable *intstr.IntOrString
+		targets        int
+		want           int
+	}{
+		{targets: 10, want: 1},
+		{maxUnavailable: ptr.To(intstr.FromInt32(3)), targets: 10, want: 3},
+		{maxUnavailable: ptr.To(intstr.FromString("25%")), targets: 10, want: 2},
+		{maxUnavailable: ptr.To(intstr.FromString("25%")), targets: 2, want: 1},
+		{maxUnavailable: ptr.To(intstr.FromInt32(0)), targets: 10, want: 1},
+	}
+	for _, tt := range tests {
+		set := &appv1.AppSet{Spec: appv1.AppSetSpec{Strategy: appv1.AppSetStrategy{MaxUnavailable: tt.maxUnavailable}}}
+		if got := appSetMaxUnavailable(set, tt.targets); got != tt.want {
+			t.Errorf("appSetMaxUnavailable(%v, %d) = %d, want %d", tt.maxUnavailable, tt.targets, got, tt.want)
+		}
+	}
+}
+
+func TestDesiredSetApp(t *testing.T) {
+	set := &appv1.AppSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+		Spec: appv1.AppSetSpec{
+			Template: appv1.AppTemplate{
+				Metadata: appv1.AppTemplateMetadata{Labels: map[string]string{"team": "platform"}},
+				Spec: appv1.AppSpec{
+					Image: "nginx:1.27",
+					Port:  8080,
+					Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "REGION", Value: "eu"}},
+				},
+			},
+			Overrides: []appv1.AppSetOverride{{
+				Namespace: "team-b",
+				Replicas:  ptr.To[int32](3),
+				Env:       []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "TENANT", Value: "b"}},
+			}},
+		},
+	}
+
+	a := desiredSetApp(set, "team-a")
+	b := desiredSetApp(set, "team-b")
+	if a.Name != "web" || a.Namespace != "team-a" || a.Labels["team"] != "platform" || a.Labels[appv1.AppSetNameLabel] != "web" {
+		t.Errorf("metadata = %+v", a.ObjectMeta)
+	}
+	if a.Spec.Replicas != nil || len(a.Spec.Env) != 2 {
+		t.Errorf("team-a spec = %+v, want the template", a.Spec)
+	}
+	if *b.Spec.Replicas != 3 || len(b.Spec.Env) != 3 || b.Spec.Env[0].Value != "debug" || b.Spec.Env[2].Name != "TENANT" {
+		t.Errorf("team-b spec = %+v, want the override", b.Spec)
+	}
+	if len(set.Spec.Template.Spec.Env) != 2 {
+		t.Error("the override modified the template")
+	}
+
+	hash := func(app *appv1.App) string { return app.Annotations[appv1.AppSetTemplateHashAnnotation] }
+	if hash(a) == "" || hash(a) == hash(b) {
+		t.Errorf("template hashes = %q, %q, want a hash per override", hash(a), hash(b))
+	}
+	if hash(desiredSetApp(set, "team-c")) != hash(a) {
+		t.Error("targets without overrides have different hashes")
+	}
+}
+
+func TestAppSetReconcile(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	set := &appv1.AppSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "appset-uid"},
+		Spec: appv1.AppSetSpec{
+			Template:          appv1.AppTemplate{Spec: newTestApp(nil).Spec},
+			Namespaces:        []string{"team-c", "missing"},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+			Overrides:         []appv1.AppSetOverride{{Namespace: "team-b", Image: "nginx:canary"}},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(set,
+			namespace("team-a", map[string]string{"tenant": "true"}),
+			namespace("team-b", map[string]string{"tenant": "true"}),
+			namespace("team-c", nil),
+			namespace("other", nil),
+		).
+		WithStatusSubresource(&appv1.App{}, &appv1.AppSet{}).
+		Build()
+	r := &AppSetReconciler{Client: c, Scheme: scheme}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(set)}
+	reconcileSet := func() *appv1.AppSet {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		got := &appv1.AppSet{}
+		if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	getApp := func(ns string) *appv1.App {
+		t.Helper()
+		app := &appv1.App{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: ns, Name: "web"}, app); err != nil {
+			t.Fatalf("App of %s: %v", ns, err)
+		}
+		return app
+	}
+	// setAvailable reports the App of ns as available or not, as the App controller would
+	setAvailable := func(ns string, available bool) {
+		t.Helper()
+		app := getApp(ns)
+		app.Status.ObservedGeneration = app.Generation
+		app.Status.ReadyReplicas, app.Status.UpdatedReplicas = 0, 0
+		if available {
+			app.Status.ReadyReplicas, app.Status.UpdatedReplicas = *app.Spec.Replicas, *app.Spec.Replicas
+		}
+		if err := c.Status().Update(ctx, app); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every target gets its App
+	got := reconcileSet()
+	if got.Status.Targets != 3 || got.Status.UpdatedTargets != 3 || got.Status.Phase != "Progressing" {
+		t.Errorf("status = %+v, want 3 updated targets progressing", got.Status)
+	}
+	if app := getApp("team-b"); app.Spec.Image != "nginx:canary" || !metav1.IsControlledBy(app, set) {
+		t.Errorf("team-b App = %+v", app)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "other", Name: "web"}, &appv1.App{}); !apierrors.IsNotFound(err) {
+		t.Errorf("App generated in a namespace that is not targeted: %v", err)
+	}
+	for _, ns := range []string{"team-a", "team-b", "team-c"} {
+		setAvailable(ns, true)
+	}
+	if got = reconcileSet(); got.Status.Phase != "Ready" || got.Status.AvailableTargets != 3 {
+		t.Errorf("status = %+v, want Ready", got.Status)
+	}
+
+	// A new template is rolled out one target at a time
+	got.Spec.Template.Spec.Image = "nginx:1.28"
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	images := func() string {
+		return getApp("team-a").Spec.Image + " " + getApp("team-b").Spec.Image + " " + getApp("team-c").Spec.Image
+	}
+	reconcileSet()
+	if got := images(); got != "nginx:1.28 nginx:canary nginx:1.27" {
+		t.Errorf("images = %s after the first step", got)
+	}
+	setAvailable("team-a", false)
+	reconcileSet()
+	if got := images(); got != "nginx:1.28 nginx:canary nginx:1.27" {
+		t.Errorf("images = %s while team-a is unavailable", got)
+	}
+	setAvailable("team-a", true)
+	reconcileSet()
+	if got := images(); got != "nginx:1.28 nginx:canary nginx:1.28" {
+		t.Errorf("images = %s after team-a is available", got)
+	}
+
+	// A target leaving the selection loses its App
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "team-a"}, ns); err != nil {
+		t.Fatal(err)
+	}
+	delete(ns.Labels, "tenant")
+	if err := c.Update(ctx, ns); err != nil {
+		t.Fatal(err)
+	}
+	if got = reconcileSet(); got.Status.Targets != 2 {
+		t.Errorf("targets = %d, want 2", got.Status.Targets)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "web"}, &appv1.App{}); !apierrors.IsNotFound(err) {
+		t.Errorf("App of a former target not deleted: %v", err)
+	}
+}
+
+func TestAppSetReconcileServiceAccountRules(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	set := &appv1.AppSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "appset-uid"},
+		Spec: appv1.AppSetSpec{
+			Template: appv1.AppTemplate{Spec: newTestApp(func(a *appv1.App) {
+				a.Spec.ServiceAccount = &appv1.ServiceAccountSpec{Rules: []rbacv1.PolicyRule{{
+					APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"},
+				}}}
+			}).Spec},
+			Namespaces: []string{"team-a"},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(set, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}).
+		WithStatusSubresource(&appv1.App{}, &appv1.AppSet{}).
+		Build()
+	r := &AppSetReconciler{Client: c, Scheme: scheme}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(set)}
+
+	// The rules would be granted by the operator in every target namespace
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "team-a", Name: "web"}, &appv1.App{}); !apierrors.IsNotFound(err) {
+		t.Errorf("App generated from a template with rules: %v", err)
+	}
+	got := &appv1.AppSet{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if cond := meta.FindStatusCondition(got.Status.Conditions, appv1.ConditionAppSetReady); got.Status.Phase != "Failed" ||
+		cond == nil || cond.Reason != "InvalidTemplate" {
+		t.Errorf("status = %+v, want the template rejected", got.Status)
+	}
+}
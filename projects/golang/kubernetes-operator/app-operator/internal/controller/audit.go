@@ -0,0 +1,63 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+)
+
+// createOrUpdate is controllerutil.CreateOrUpdate for the objects owned by
+// the App, recording the change in the audit log
+func (r *AppReconciler) createOrUpdate(ctx context.Context, app *appv1.App, obj client.Object, f controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	var before client.Object
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		// The live object, before the mutation; nothing is live on creation
+		if obj.GetResourceVersion() != "" {
+			before = obj.DeepCopyObject().(client.Object)
+		}
+		return f()
+	})
+	if err != nil {
+		return op, err
+	}
+	r.recordChange(ctx, app, op, before, obj)
+	return op, nil
+}
+
+// deleteObject deletes an object owned by the App, recording the deletion in the audit log
+func (r *AppReconciler) deleteObject(ctx context.Context, app *appv1.App, obj client.Object) error {
+	if err := r.Delete(ctx, obj); err != nil {
+		return err
+	}
+	r.recordChange(ctx, app, audit.OperationDeleted, obj, nil)
+	return nil
+}
+
+// recordChange writes the audit record of a change. The change is made at
+// this point, a failure to record it does not fail the reconciliation.
+func (r *AppReconciler) recordChange(ctx context.Context, app *appv1.App, op controllerutil.OperationResult, before, after client.Object) {
+	if err := r.Audit.Log(ctx, app, op, before, after); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to write audit record", "operation", op)
+	}
+}
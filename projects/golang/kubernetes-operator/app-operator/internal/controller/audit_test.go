@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+)
+
+func TestReconcileAudit(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Ingress = &appv1.IngressSpec{Host: "web.example.com"}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	var buf bytes.Buffer
+	r := &AppReconciler{Client: c, Scheme: scheme, Audit: &audit.Logger{Sink: audit.NewWriterSink(&buf), Scheme: scheme}}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+	reconcileRecords := func() []audit.Record {
+		t.Helper()
+		buf.Reset()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		var records []audit.Record
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var rec audit.Record
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatal(err)
+			}
+			records = append(records, rec)
+		}
+		return records
+	}
+	summary := func(records []audit.Record) []string {
+		var s []string
+		for _, rec := range records {
+			s = append(s, string(rec.Operation)+" "+rec.Object.Kind+" "+rec.Object.Name)
+		}
+		return s
+	}
+
+	records := reconcileRecords()
+	want := []string{"created Deployment web-app", "created Service web-svc", "created Ingress web-ingress"}
+	if diff := cmp.Diff(want, summary(records)); diff != "" {
+		t.Fatalf("records mismatch (-want +got):\n%s", diff)
+	}
+	if records[0].App.Name != "web" || len(records[0].Changes) == 0 {
+		t.Errorf("record = %+v", records[0])
+	}
+
+	if records := reconcileRecords(); len(records) != 0 {
+		t.Errorf("unchanged objects recorded: %v", summary(records))
+	}
+
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Image = "nginx:1.28"
+	app.Spec.Ingress = nil
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	records = reconcileRecords()
+	want = []string{"updated Deployment web-app", "deleted Ingress web-ingress"}
+	if diff := cmp.Diff(want, summary(records)); diff != "" {
+		t.Fatalf("records mismatch (-want +got):\n%s", diff)
+	}
+	wantChanges := []audit.Change{{Path: "spec.template.spec.containers[0].image", Old: "nginx:1.27", New: "nginx:1.28"}}
+	if diff := cmp.Diff(wantChanges, records[0].Changes); diff != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/usage"
+)
+
+// maxRequestsHistory is the number of changes kept in status.autoTune.history
+const maxRequestsHistory = 10
+
+// tunedResources are the requests adjusted by resources.autoTune
+var tunedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// reconcileAutoTune moves the requests of the app container toward the p95
+// usage of the window, within the bounds of the policy and only in its rollout
+// windows. The requests are stored in status.autoTune, read by containerResources.
+// New requests are persisted before the Deployment is updated: were the status
+// update to fail after the rollout, the next reconciliation would roll the pods
+// back to the previous requests.
+func (r *AppReconciler) reconcileAutoTune(ctx context.Context, app *appv1.App, now time.Time) error {
+	if app.Spec.Resources == nil || app.Spec.Resources.AutoTune == nil {
+		app.Status.AutoTune = nil
+		return nil
+	}
+	if app.Status.AutoTune == nil {
+		app.Status.AutoTune = &appv1.AutoTuneStatus{}
+	}
+	if r.UsageRecorder == nil || !inRolloutWindow(app.Spec.Resources.AutoTune.RolloutWindows, now) {
+		return nil
+	}
+	samples := r.UsageRecorder.Samples(client.ObjectKeyFromObject(app), now)
+	if len(samples) < minRecommendationSamples {
+		return nil
+	}
+
+	before := containerResources(app).Requests
+	after, changes := tunedRequests(app, samples)
+	if len(changes) == 0 {
+		return nil
+	}
+	message := "p95 usage: " + strings.Join(changes, ", ")
+	log.FromContext(ctx).Info("Tuning requests", "before", before, "after", after, "message", message)
+
+	status := app.Status.AutoTune
+	status.Requests = after
+	status.History = append(status.History, appv1.RequestsChange{
+		Time:    metav1.NewTime(now),
+		Before:  before,
+		After:   after,
+		Message: message,
+	})
+	if len(status.History) > maxRequestsHistory {
+		status.History = status.History[len(status.History)-maxRequestsHistory:]
+	}
+	return r.Status().Update(ctx, app)
+}
+
+// tunedRequests returns the requests fitting the p95 usage of the samples,
+// and the description of the changes worth a rollout
+func tunedRequests(app *appv1.App, samples []usage.Sample) (corev1.ResourceList, []string) {
+	policy := app.Spec.Resources.AutoTune
+	minChange := float64(policy.MinChangePercent)
+	if minChange == 0 {
+		minChange = 10
+	}
+
+	resources := containerResources(app)
+	requests := resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	var changes []string
+	for _, name := range tunedResources {
+		var values []float64
+		for _, s := range samples {
+			if name == corev1.ResourceCPU {
+				values = append(values, s.CPU...)
+			} else {
+				values = append(values, s.Memory...)
+			}
+		}
+		p95 := usage.Percentile(values, 95)
+		target := usageQuantity(name, p95*recommendationHeadroom)
+		target = clampQuantity(target, policy.MinAllowed, policy.MaxAllowed, resources.Limits, name)
+
+		current, ok := requests[name]
+		if ok && !current.IsZero() {
+			change := math.Abs(usageAmount(name, target)-usageAmount(name, current)) / usageAmount(name, current) * 100
+			if change < minChange {
+				continue
+			}
+		}
+		requests[name] = target
+		p95Quantity := usageQuantity(name, p95)
+		changes = append(changes, fmt.Sprintf("%s %s", name, &p95Quantity))
+	}
+	return requests, changes
+}
+
+// clampQuantity bounds q by the policy, and by the limit since a request
+// cannot exceed its limit
+func clampQuantity(q resource.Quantity, minAllowed, maxAllowed, limits corev1.ResourceList, name corev1.ResourceName) resource.Quantity {
+	if lowest, ok := minAllowed[name]; ok && q.Cmp(lowest) < 0 {
+		q = lowest.DeepCopy()
+	}
+	if highest, ok := maxAllowed[name]; ok && q.Cmp(highest) > 0 {
+		q = highest.DeepCopy()
+	}
+	if limit, ok := limits[name]; ok && q.Cmp(limit) > 0 {
+		q = limit.DeepCopy()
+	}
+	return q
+}
+
+// inRolloutWindow reports whether now, in UTC, is within one of the windows
+func inRolloutWindow(windows []appv1.RolloutWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	now = now.UTC()
+	clock := now.Format("15:04")
+	today := appv1.Weekday(now.Weekday().String()[:3])
+	yesterday := appv1.Weekday(now.AddDate(0, 0, -1).Weekday().String()[:3])
+	for _, w := range windows {
+		onDay := func(day appv1.Weekday) bool { return len(w.Days) == 0 || slices.Contains(w.Days, day) }
+		switch {
+		case w.Start <= w.End:
+			if onDay(today) && clock >= w.Start && clock < w.End {
+				return true
+			}
+		// Spanning midnight: the window belongs to the day it starts
+		case onDay(today) && clock >= w.Start, onDay(yesterday) && clock < w.End:
+			return true
+		}
+	}
+	return false
+}
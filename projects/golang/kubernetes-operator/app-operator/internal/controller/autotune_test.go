@@ -0,0 +1,213 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/usage"
+)
+
+func TestInRolloutWindow(t *testing.T) {
+	// 2026-01-03 is a Saturday
+	at := func(day int, clock string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", fmt.Sprintf("2026-01-%02d %s", day, clock))
+		return t
+	}
+	nightly := []appv1.RolloutWindow{{Start: "22:00", End: "02:00", Days: []appv1.Weekday{"Sat"}}}
+	tests := []struct {
+		name    string
+		windows []appv1.RolloutWindow
+		now     time.Time
+		want    bool
+	}{
+		{name: "no window", now: at(3, "12:00"), want: true},
+		{name: "within", windows: []appv1.RolloutWindow{{Start: "02:00", End: "05:00"}}, now: at(3, "02:00"), want: true},
+		{name: "end excluded", windows: []appv1.RolloutWindow{{Start: "02:00", End: "05:00"}}, now: at(3, "05:00")},
+		{name: "other day", windows: []appv1.RolloutWindow{{Start: "02:00", End: "05:00", Days: []appv1.Weekday{"Sun"}}}, now: at(3, "03:00")},
+		{name: "before midnight", windows: nightly, now: at(3, "23:00"), want: true},
+		{name: "after midnight", windows: nightly, now: at(4, "01:00"), want: true},
+		{name: "after midnight of another day", windows: nightly, now: at(3, "01:00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inRolloutWindow(tt.windows, tt.now); got != tt.want {
+				t.Errorf("inRolloutWindow(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileAutoTune(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Resources = &appv1.ResourcesSpec{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			AutoTune: &appv1.AutoTuneSpec{
+				MinAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+			},
+		}
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(&appv1.App{}).Build()
+	r := &AppReconciler{Client: c, Scheme: scheme, UsageRecorder: usage.NewRecorder(time.Hour)}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	// 100m of CPU is below the policy, 400Mi of memory is over the request
+	now := time.Now()
+	for i := minRecommendationSamples - 1; i >= 0; i-- {
+		r.UsageRecorder.Add(req.NamespacedName, usage.Sample{
+			Time:   now.Add(-time.Duration(i) * time.Minute),
+			CPU:    []float64{100},
+			Memory: []float64{400 << 20},
+		})
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	requests := dep.Spec.Template.Spec.Containers[0].Resources.Requests
+	if cpu := requests[corev1.ResourceCPU]; cpu.String() != "200m" {
+		t.Errorf("cpu request = %s, want the 200m lower bound", &cpu)
+	}
+	// 400Mi plus headroom is capped by the limit
+	if memory := requests[corev1.ResourceMemory]; memory.String() != "480Mi" {
+		t.Errorf("memory request = %s, want 480Mi", &memory)
+	}
+
+	got := &appv1.App{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.AutoTune == nil || len(got.Status.AutoTune.History) != 1 {
+		t.Fatalf("autoTune status = %+v, want one change", got.Status.AutoTune)
+	}
+	change := got.Status.AutoTune.History[0]
+	if before := change.Before[corev1.ResourceCPU]; before.String() != "1" {
+		t.Errorf("cpu before = %s, want 1", &before)
+	}
+
+	// Same usage: no new change
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(got.Status.AutoTune.History); n != 1 {
+		t.Errorf("len(history) = %d after a stable usage, want 1", n)
+	}
+
+	// Removing autoTune restores spec.resources.requests
+	got.Spec.Resources.AutoTune = nil
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatal(err)
+	}
+	if cpu := dep.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; cpu.String() != "1" {
+		t.Errorf("cpu request = %s after disabling autoTune, want 1", &cpu)
+	}
+}
+
+func TestReconcileAutoTunePersistedBeforeRollout(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Resources = &appv1.ResourcesSpec{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			AutoTune: &appv1.AutoTuneSpec{},
+		}
+	})
+	// The rollout fails
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(&appv1.App{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*appsv1.Deployment); ok {
+					return errors.New("admission webhook denied the request")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+	r := &AppReconciler{Client: c, Scheme: scheme, UsageRecorder: usage.NewRecorder(time.Hour)}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+	now := time.Now()
+	for i := minRecommendationSamples - 1; i >= 0; i-- {
+		r.UsageRecorder.Add(req.NamespacedName, usage.Sample{
+			Time: now.Add(-time.Duration(i) * time.Minute), CPU: []float64{100}, Memory: []float64{100 << 20},
+		})
+	}
+	if _, err := r.Reconcile(ctx, req); err == nil {
+		t.Fatal("Reconcile succeeded, want the Deployment error")
+	}
+
+	got := &appv1.App{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.AutoTune == nil || len(got.Status.AutoTune.Requests) == 0 || len(got.Status.AutoTune.History) != 1 {
+		t.Errorf("autoTune status = %+v, want the tuned requests persisted before the rollout", got.Status.AutoTune)
+	}
+}
+
+func TestReconcileAutoTuneOutsideRolloutWindow(t *testing.T) {
+	now := time.Now().UTC()
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Resources = &appv1.ResourcesSpec{AutoTune: &appv1.AutoTuneSpec{RolloutWindows: []appv1.RolloutWindow{{
+			Start: now.Add(time.Hour).Format("15:04"),
+			End:   now.Add(2 * time.Hour).Format("15:04"),
+		}}}}
+	})
+	r := &AppReconciler{UsageRecorder: usage.NewRecorder(time.Hour)}
+	for i := minRecommendationSamples - 1; i >= 0; i-- {
+		r.UsageRecorder.Add(client.ObjectKeyFromObject(app), usage.Sample{
+			Time: now.Add(-time.Duration(i) * time.Minute), CPU: []float64{100}, Memory: []float64{100 << 20},
+		})
+	}
+
+	if err := r.reconcileAutoTune(context.Background(), app, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Status.AutoTune.Requests) != 0 || len(app.Status.AutoTune.History) != 0 {
+		t.Errorf("autoTune status = %+v, want no change outside the rollout windows", app.Status.AutoTune)
+	}
+}
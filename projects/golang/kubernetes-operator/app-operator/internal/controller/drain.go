@@ -0,0 +1,64 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// drain returns a reconciler running r with a context detached from the
+// shutdown of the manager. controller-runtime cancels the context of the
+// reconciles in flight on shutdown, failing their API calls half way; drained
+// reconciles get up to timeout more to complete instead. The manager waits
+// for them up to its graceful shutdown timeout before releasing the leader
+// lease, so timeout must be shorter for the next leader never to run
+// concurrently with them, see ValidateDrainTimeout. A zero timeout returns r
+// unchanged.
+func drain(r reconcile.Reconciler, timeout time.Duration) reconcile.Reconciler {
+	if timeout <= 0 {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		drainCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		stop := context.AfterFunc(ctx, func() {
+			timer := time.AfterFunc(timeout, cancel)
+			context.AfterFunc(drainCtx, func() { timer.Stop() })
+		})
+		defer stop()
+		return r.Reconcile(drainCtx, req)
+	})
+}
+
+// ValidateDrainTimeout checks that the drained reconciles end before the
+// manager gives up waiting for them. The manager starts its graceful shutdown
+// timer before cancelling the reconciles, so a drain as long as the graceful
+// shutdown would still write once the lease is released. A negative graceful
+// shutdown timeout waits forever.
+func ValidateDrainTimeout(drainTimeout, gracefulShutdownTimeout time.Duration) error {
+	if drainTimeout < 0 {
+		return fmt.Errorf("drain timeout %s is negative", drainTimeout)
+	}
+	if drainTimeout > 0 && gracefulShutdownTimeout >= 0 && drainTimeout >= gracefulShutdownTimeout {
+		return fmt.Errorf("drain timeout %s must be less than the graceful shutdown timeout %s", drainTimeout, gracefulShutdownTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDrain(t *testing.T) {
+	inner := make(chan context.Context, 1)
+	release := make(chan struct{})
+	r := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		inner <- ctx
+		<-release
+		return reconcile.Result{}, ctx.Err()
+	})
+
+	// The reconcile in flight outlives the manager
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := drain(r, time.Minute).Reconcile(ctx, reconcile.Request{})
+		errs <- err
+	}()
+	drainCtx := <-inner
+	cancel()
+	select {
+	case <-drainCtx.Done():
+		t.Fatal("reconcile context cancelled with the manager")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-errs; err != nil {
+		t.Errorf("Reconcile() = %v", err)
+	}
+	if drainCtx.Err() == nil {
+		t.Error("reconcile context not released once done")
+	}
+
+	// Up to the timeout
+	release = make(chan struct{})
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		_, err := drain(r, 10*time.Millisecond).Reconcile(ctx, reconcile.Request{})
+		errs <- err
+	}()
+	drainCtx = <-inner
+	cancel()
+	select {
+	case <-drainCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconcile context not cancelled after the drain timeout")
+	}
+	close(release)
+	if err := <-errs; err == nil {
+		t.Error("Reconcile() = nil, want the context error")
+	}
+}
+
+func TestDrainEndsBeforeGracefulShutdown(t *testing.T) {
+	const gracefulShutdownTimeout = 200 * time.Millisecond
+	drainTimeout := 50 * time.Millisecond
+	if err := ValidateDrainTimeout(drainTimeout, gracefulShutdownTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// The reconcile outlives the drain timeout, it only returns once cancelled
+	r := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		<-ctx.Done()
+		return reconcile.Result{}, ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = drain(r, drainTimeout).Reconcile(ctx, reconcile.Request{})
+	}()
+
+	// As controller-runtime, the grace period starts before the reconciles are cancelled
+	grace := time.After(gracefulShutdownTimeout)
+	cancel()
+	select {
+	case <-done:
+	case <-grace:
+		t.Fatal("reconcile still running once the graceful shutdown timed out")
+	}
+}
+
+func TestValidateDrainTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		drain, graceful time.Duration
+		wantErr         bool
+	}{
+		{drain: 25 * time.Second, graceful: 30 * time.Second},
+		{drain: 30 * time.Second, graceful: 30 * time.Second, wantErr: true},
+		{drain: time.Minute, graceful: 30 * time.Second, wantErr: true},
+		{drain: time.Second, graceful: 0, wantErr: true},
+		{drain: 0, graceful: 0},
+		{drain: time.Hour, graceful: -1},
+		{drain: -time.Second, graceful: 30 * time.Second, wantErr: true},
+	} {
+		if err := ValidateDrainTimeout(tc.drain, tc.graceful); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateDrainTimeout(%s, %s) = %v, want error %t", tc.drain, tc.graceful, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// defaultHealthCheckTimeout matches the default of HealthChecksSpec.Timeout
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// healthCheckGateRetryInterval is how often the checks are retried while pods
+// wait for the readiness gate
+const healthCheckGateRetryInterval = 5 * time.Second
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
+
+// reconcileHealthChecks performs spec.healthChecks against the Service of the
+// App, reported by the ExternallyHealthy condition, and against each pod when
+// the readiness gate is enabled, reported by the HealthCheckReadinessGate
+// condition of the pod. Failed checks are not errors.
+//
+// The checks run once per interval, not on every event of the App, and all
+// of them in parallel, so that an unreachable app delays the reconciliation
+// by one check timeout at most.
+func (r *AppReconciler) reconcileHealthChecks(ctx context.Context, app *appv1.App) error {
+	spec := app.Spec.HealthChecks
+	key := client.ObjectKeyFromObject(app)
+	if spec == nil || r.HealthProber == nil {
+		r.healthCheckRuns.forget(key)
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionExternallyHealthy)
+		return nil
+	}
+	now := time.Now()
+	if !r.healthCheckRuns.due(key, app.Generation, now, r.healthCheckInterval(app)) {
+		return nil
+	}
+
+	// The name of a headless Service resolves to the pod IPs, not to a cluster IP
+	servicePort := func(p appv1.ServicePort) int32 { return p.Port }
+	if app.Spec.Service != nil && app.Spec.Service.Type == appv1.ServiceTypeHeadless {
+		servicePort = func(p appv1.ServicePort) int32 { return p.TargetPort }
+	}
+	targets := []healthCheckTarget{{
+		host: fmt.Sprintf("%s.%s.svc", app.ServiceName(), app.Namespace),
+		port: servicePort,
+	}}
+	var pods []*corev1.Pod
+	if spec.ReadinessGate {
+		list := &corev1.PodList{}
+		if err := r.List(ctx, list, client.InNamespace(app.Namespace), client.MatchingLabels(app.SelectorLabels())); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			pod := &list.Items[i]
+			if pod.Status.PodIP == "" || pod.Status.Phase != corev1.PodRunning || !hasReadinessGate(pod) {
+				continue
+			}
+			pods = append(pods, pod)
+			targets = append(targets, healthCheckTarget{
+				host: pod.Status.PodIP,
+				port: func(p appv1.ServicePort) int32 { return p.TargetPort },
+			})
+		}
+	}
+	failures := r.probeAll(ctx, app, targets)
+
+	cond := metav1.Condition{
+		Type:               appv1.ConditionExternallyHealthy,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ChecksPassed",
+		Message:            fmt.Sprintf("%d checks passed against Service %s", len(spec.Checks), app.ServiceName()),
+		ObservedGeneration: app.Generation,
+	}
+	if len(failures[0]) > 0 {
+		cond.Status, cond.Reason = metav1.ConditionFalse, "CheckFailed"
+		cond.Message = strings.Join(failures[0], "; ")
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
+
+	for i, pod := range pods {
+		if err := r.gatePod(ctx, pod, failures[i+1]); err != nil {
+			return err
+		}
+	}
+	r.healthCheckRuns.record(key, app.Generation, now)
+	return nil
+}
+
+// healthCheckInterval returns how often the checks of the App are performed:
+// the retry interval while pods wait for the readiness gate, as in requeue
+func (r *AppReconciler) healthCheckInterval(app *appv1.App) time.Duration {
+	if app.Spec.HealthChecks.ReadinessGate && app.Status.ReadyReplicas < ptr.Deref(app.Spec.Replicas, 1) {
+		return healthCheckGateRetryInterval
+	}
+	return r.HealthCheckInterval
+}
+
+// gatePod sets the readiness gate condition of a pod to the result of the
+// checks against the pod
+func (r *AppReconciler) gatePod(ctx context.Context, pod *corev1.Pod, failures []string) error {
+	cond := corev1.PodCondition{
+		Type:   appv1.HealthCheckReadinessGate,
+		Status: corev1.ConditionTrue,
+		Reason: "ChecksPassed",
+	}
+	if len(failures) > 0 {
+		cond.Status, cond.Reason = corev1.ConditionFalse, "CheckFailed"
+		cond.Message = strings.Join(failures, "; ")
+	}
+
+	live := podCondition(pod, appv1.HealthCheckReadinessGate)
+	if live != nil && live.Status == cond.Status && live.Message == cond.Message {
+		return nil
+	}
+	cond.LastTransitionTime = metav1.Now()
+	if live != nil && live.Status == cond.Status {
+		cond.LastTransitionTime = live.LastTransitionTime
+	}
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+	if live != nil {
+		*live = cond
+	} else {
+		pod.Status.Conditions = append(pod.Status.Conditions, cond)
+	}
+	if err := r.Status().Patch(ctx, pod, patch); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Pod readiness gate updated", "pod", pod.Name, "status", cond.Status)
+	return nil
+}
+
+// healthCheckTarget is a host the checks are performed against, on the port
+// picked from the Service port of each check
+type healthCheckTarget struct {
+	host string
+	port func(appv1.ServicePort) int32
+}
+
+// probeAll performs every check of the App against every target in parallel,
+// within the timeout of one check, and returns the failures of each target
+// in the order of the checks
+func (r *AppReconciler) probeAll(ctx context.Context, app *appv1.App, targets []healthCheckTarget) [][]string {
+	spec := app.Spec.HealthChecks
+	timeout := defaultHealthCheckTimeout
+	if spec.Timeout != nil {
+		timeout = spec.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// errs[i][j] is the result of check j against target i
+	errs := make([][]error, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		errs[i] = make([]error, len(spec.Checks))
+		for j, check := range spec.Checks {
+			p, ok := healthCheckPort(app, check)
+			if !ok {
+				errs[i][j] = fmt.Errorf("no Service port %q", check.Port)
+				continue
+			}
+			address := net.JoinHostPort(target.host, strconv.Itoa(int(target.port(p))))
+			wg.Add(1)
+			go func(i, j int, check appv1.HealthCheck) {
+				defer wg.Done()
+				errs[i][j] = r.HealthProber.Probe(ctx, address, check)
+			}(i, j, check)
+		}
+	}
+	wg.Wait()
+
+	failures := make([][]string, len(targets))
+	for i := range targets {
+		for j, check := range spec.Checks {
+			if errs[i][j] != nil {
+				failures[i] = append(failures[i], fmt.Sprintf("%s: %v", check.Name, errs[i][j]))
+			}
+		}
+	}
+	return failures
+}
+
+// healthCheckRuns remembers when the checks of each App were last performed
+type healthCheckRuns struct {
+	mu   sync.Mutex
+	runs map[types.NamespacedName]healthCheckRun
+}
+
+type healthCheckRun struct {
+	time       time.Time
+	generation int64
+}
+
+// due reports whether the checks of an App are to be performed: on a new
+// generation of its spec, or once the interval has passed
+func (h *healthCheckRuns) due(key types.NamespacedName, generation int64, now time.Time, interval time.Duration) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[key]
+	return !ok || run.generation != generation || now.Sub(run.time) >= interval
+}
+
+// record remembers that the checks of an App were performed at now
+func (h *healthCheckRuns) record(key types.NamespacedName, generation int64, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.runs == nil {
+		h.runs = map[types.NamespacedName]healthCheckRun{}
+	}
+	h.runs[key] = healthCheckRun{time: now, generation: generation}
+}
+
+// forget drops the last run of a deleted App, or of an App without checks
+func (h *healthCheckRuns) forget(key types.NamespacedName) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.runs, key)
+}
+
+// healthCheckPort returns the Service port targeted by a check
+func healthCheckPort(app *appv1.App, check appv1.HealthCheck) (appv1.ServicePort, bool) {
+	ports := servicePorts(app)
+	if check.Port == "" {
+		return ports[0], true
+	}
+	for _, p := range ports {
+		if p.Name == check.Port {
+			return p, true
+		}
+	}
+	return appv1.ServicePort{}, false
+}
+
+// podReadinessGates returns the readiness gates of the pod template
+func podReadinessGates(app *appv1.App) []corev1.PodReadinessGate {
+	if app.Spec.HealthChecks == nil || !app.Spec.HealthChecks.ReadinessGate {
+		return nil
+	}
+	return []corev1.PodReadinessGate{{ConditionType: appv1.HealthCheckReadinessGate}}
+}
+
+func hasReadinessGate(pod *corev1.Pod) bool {
+	for _, g := range pod.Spec.ReadinessGates {
+		if g.ConditionType == appv1.HealthCheckReadinessGate {
+			return true
+		}
+	}
+	return false
+}
+
+func podCondition(pod *corev1.Pod, condType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == condType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// fakeProber fails the checks of the addresses in down
+type fakeProber struct {
+	mu    sync.Mutex
+	down  map[string]bool
+	calls []string
+}
+
+func (p *fakeProber) Probe(_ context.Context, address string, check appv1.HealthCheck) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, check.Name+"@"+address)
+	if p.down[address] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestReconcileHealthChecks(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Service = &appv1.ServiceSpec{Ports: []appv1.ServicePort{
+			{Name: "http", Port: 80, TargetPort: 8080},
+			{Name: "grpc", Port: 9090},
+		}}
+		a.Spec.HealthChecks = &appv1.HealthChecksSpec{
+			Checks: []appv1.HealthCheck{
+				{Name: "http", HTTP: &appv1.HTTPHealthCheck{Path: "/healthz"}},
+				{Name: "grpc", Port: "grpc", GRPC: &appv1.GRPCHealthCheck{}},
+			},
+			ReadinessGate: true,
+		}
+	})
+	pod := func(name, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: app.SelectorLabels()},
+			Spec: corev1.PodSpec{
+				ReadinessGates: []corev1.PodReadinessGate{{ConditionType: appv1.HealthCheckReadinessGate}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app, pod("web-1", "10.0.0.1"), pod("web-2", "10.0.0.2")).
+		WithStatusSubresource(&appv1.App{}, &corev1.Pod{}).
+		Build()
+	prober := &fakeProber{down: map[string]bool{"10.0.0.2:9090": true}}
+	r := &AppReconciler{Client: c, Scheme: scheme, HealthProber: prober, HealthCheckInterval: time.Minute}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != healthCheckGateRetryInterval {
+		t.Errorf("RequeueAfter = %s while pods wait for the readiness gate", res.RequeueAfter)
+	}
+	assertCondition(t, c, app, appv1.ConditionExternallyHealthy, "ChecksPassed")
+	slices.Sort(prober.calls)
+	if want := []string{
+		"grpc@10.0.0.1:9090", "grpc@10.0.0.2:9090", "grpc@web-svc.default.svc:9090",
+		"http@10.0.0.1:8080", "http@10.0.0.2:8080", "http@web-svc.default.svc:80",
+	}; !slices.Equal(prober.calls, want) {
+		t.Errorf("checks = %v, want %v", prober.calls, want)
+	}
+
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: app.DeploymentName()}, dep); err != nil {
+		t.Fatal(err)
+	}
+	if gates := dep.Spec.Template.Spec.ReadinessGates; len(gates) != 1 || gates[0].ConditionType != appv1.HealthCheckReadinessGate {
+		t.Errorf("readiness gates = %v", gates)
+	}
+	for name, want := range map[string]corev1.ConditionStatus{"web-1": corev1.ConditionTrue, "web-2": corev1.ConditionFalse} {
+		got := &corev1.Pod{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, got); err != nil {
+			t.Fatal(err)
+		}
+		if cond := podCondition(got, appv1.HealthCheckReadinessGate); cond == nil || cond.Status != want {
+			t.Errorf("%s readiness gate = %+v, want %s", name, cond, want)
+		}
+	}
+
+	// The checks are not performed again within the interval
+	prober.calls = nil
+	prober.down["web-svc.default.svc:80"] = true
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(prober.calls) != 0 {
+		t.Errorf("checks = %v within the interval", prober.calls)
+	}
+	assertCondition(t, c, app, appv1.ConditionExternallyHealthy, "ChecksPassed")
+
+	// The Service stops answering
+	key := client.ObjectKeyFromObject(app)
+	r.healthCheckRuns.record(key, app.Generation, time.Now().Add(-healthCheckGateRetryInterval))
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionExternallyHealthy, "CheckFailed")
+}
+
+func TestReconcileHealthChecksHeadlessService(t *testing.T) {
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Service = &appv1.ServiceSpec{
+			Type:  appv1.ServiceTypeHeadless,
+			Ports: []appv1.ServicePort{{Name: "http", Port: 80, TargetPort: 8080}},
+		}
+		a.Spec.HealthChecks = &appv1.HealthChecksSpec{
+			Checks: []appv1.HealthCheck{{Name: "http", HTTP: &appv1.HTTPHealthCheck{Path: "/healthz"}}},
+		}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	prober := &fakeProber{}
+	r := &AppReconciler{Client: c, Scheme: scheme, HealthProber: prober, HealthCheckInterval: time.Minute}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}); err != nil {
+		t.Fatal(err)
+	}
+	// The name resolves to the pod IPs, listening on the target port
+	if want := []string{"http@web-svc.default.svc:8080"}; !slices.Equal(prober.calls, want) {
+		t.Errorf("checks = %v, want %v", prober.calls, want)
+	}
+}
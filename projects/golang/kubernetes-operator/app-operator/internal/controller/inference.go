@@ -0,0 +1,196 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// modelsPath is where the models are stored in the containers of an inference App
+const modelsPath = "/models"
+
+// pullModelScript pulls $OLLAMA_MODEL with a temporary server, as the ollama
+// CLI talks to the server of OLLAMA_HOST
+const pullModelScript = `ollama serve & server=$!
+until ollama list >/dev/null 2>&1; do sleep 1; done
+ollama pull "$OLLAMA_MODEL"
+status=$?
+kill $server
+exit $status`
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileModelCache creates the PersistentVolumeClaim of spec.inference.cache,
+// or deletes it once it is no longer wanted. It runs before the Deployment so
+// that the pods find their volume.
+func (r *AppReconciler) reconcileModelCache(ctx context.Context, app *appv1.App) error {
+	pvc := desiredModelCache(app)
+	if pvc == nil {
+		return r.deleteOwned(ctx, app, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      app.ModelCacheName(),
+			Namespace: app.Namespace,
+		}})
+	}
+
+	desired := pvc.DeepCopy()
+	op, err := r.createOrUpdate(ctx, app, pvc, func() error {
+		for k, v := range desired.Labels {
+			metav1.SetMetaDataLabel(&pvc.ObjectMeta, k, v)
+		}
+		// The spec is immutable once bound, except to expand the volume
+		if pvc.CreationTimestamp.IsZero() {
+			pvc.Spec = desired.Spec
+		} else if size := desired.Spec.Resources.Requests[corev1.ResourceStorage]; size.Cmp(pvc.Spec.Resources.Requests[corev1.ResourceStorage]) > 0 {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		}
+		return ctrl.SetControllerReference(app, pvc, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("PersistentVolumeClaim reconciled", "operation", op, "name", pvc.Name)
+	return nil
+}
+
+// desiredModelCache returns the PersistentVolumeClaim of the models, or nil
+// when spec.inference.cache is not set
+func desiredModelCache(app *appv1.App) *corev1.PersistentVolumeClaim {
+	if app.Spec.Inference == nil || app.Spec.Inference.Cache == nil {
+		return nil
+	}
+	spec := app.Spec.Inference.Cache
+
+	size := resource.MustParse("20Gi")
+	if spec.Size != nil {
+		size = *spec.Size
+	}
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.ModelCacheName(),
+			Namespace: app.Namespace,
+			Labels:    app.SelectorLabels(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{modelCacheAccessMode(app)},
+			StorageClassName: spec.StorageClassName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+}
+
+// modelCacheAccessMode returns the access mode of the PersistentVolumeClaim of the models
+func modelCacheAccessMode(app *appv1.App) corev1.PersistentVolumeAccessMode {
+	if mode := app.Spec.Inference.Cache.AccessMode; mode != "" {
+		return mode
+	}
+	return corev1.ReadWriteOnce
+}
+
+// recreatePods reports whether the pods must be recreated rather than rolled
+// out, when they share a volume that only one node can attach
+func recreatePods(app *appv1.App) bool {
+	return app.Spec.Inference != nil && app.Spec.Inference.Cache != nil &&
+		modelCacheAccessMode(app) == corev1.ReadWriteOnce
+}
+
+// inferenceEnv returns the environment of the Ollama server: it listens on
+// spec.port, stores the models in their volume and keeps the model loaded
+func inferenceEnv(app *appv1.App) []corev1.EnvVar {
+	if app.Spec.Inference == nil {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: "OLLAMA_HOST", Value: fmt.Sprintf("0.0.0.0:%d", app.Spec.Port)},
+		{Name: "OLLAMA_MODELS", Value: modelsPath},
+		{Name: "OLLAMA_KEEP_ALIVE", Value: "-1"},
+	}
+}
+
+// pullModelContainer returns the init container pulling the model into its
+// volume. It gets the resources of the app container, which run after it, so
+// it does not raise the requests of the pod and meets the same policy.
+func pullModelContainer(app *appv1.App) corev1.Container {
+	return corev1.Container{
+		Name:    appv1.PullModelContainerName,
+		Image:   app.Spec.Image,
+		Command: []string{"sh", "-c", pullModelScript},
+		Env: append(inferenceEnv(app),
+			corev1.EnvVar{Name: "OLLAMA_MODEL", Value: app.Spec.Inference.Model}),
+		VolumeMounts:    []corev1.VolumeMount{modelsVolumeMount()},
+		SecurityContext: app.Spec.SecurityContext,
+		Resources:       containerResources(app),
+	}
+}
+
+// modelsVolume returns the pod volume of the models: the PersistentVolumeClaim
+// of the cache, or an emptyDir shared by the init container and the app container
+func modelsVolume(app *appv1.App) corev1.Volume {
+	volume := corev1.Volume{Name: appv1.ModelsVolumeName}
+	if app.Spec.Inference.Cache != nil {
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: app.ModelCacheName()}
+	} else {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	return volume
+}
+
+// modelsVolumeMount returns the mount of the models
+func modelsVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{Name: appv1.ModelsVolumeName, MountPath: modelsPath}
+}
+
+// containerReadinessProbe returns the readiness probe of the app container.
+// An inference App is ready once its model is loaded: running the model with
+// an empty prompt loads it, then returns at once as it stays loaded.
+func containerReadinessProbe(app *appv1.App) *corev1.Probe {
+	if app.Spec.Inference == nil {
+		return nil
+	}
+	// All the fields are set, the API server would default them otherwise
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{
+			Command: []string{"ollama", "run", app.Spec.Inference.Model, ""},
+		}},
+		TimeoutSeconds:   300,
+		PeriodSeconds:    10,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	}
+}
+
+// inferenceStatus returns the model served and its endpoint, or nil when
+// spec.inference is not set
+func inferenceStatus(app *appv1.App) *appv1.InferenceStatus {
+	if app.Spec.Inference == nil {
+		return nil
+	}
+	return &appv1.InferenceStatus{
+		Model:    app.Spec.Inference.Model,
+		Endpoint: fmt.Sprintf("http://%s.%s.svc:%d/v1", app.ServiceName(), app.Namespace, servicePorts(app)[0].Port),
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestReconcileInference(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	size := resource.MustParse("5Gi")
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Image = "ollama/ollama:0.5.7"
+		a.Spec.Port = 11434
+		a.Spec.Inference = &appv1.InferenceSpec{
+			Model:   "qwen2.5:0.5b",
+			Profile: appv1.InferenceProfileGPU,
+			Cache:   &appv1.ModelCacheSpec{Size: &size},
+		}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{Client: c, Scheme: scheme}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+	pvcKey := client.ObjectKey{Namespace: "default", Name: "web-models"}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := c.Get(ctx, pvcKey, pvc); err != nil {
+		t.Fatal(err)
+	}
+	if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.Cmp(size) != 0 || pvc.Spec.AccessModes[0] != corev1.ReadWriteOnce {
+		t.Errorf("PersistentVolumeClaim = %s %v, want 5Gi ReadWriteOnce", got.String(), pvc.Spec.AccessModes)
+	}
+
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	pod := dep.Spec.Template.Spec
+	if len(pod.InitContainers) != 1 || pod.InitContainers[0].Name != appv1.PullModelContainerName || pod.InitContainers[0].Image != "ollama/ollama:0.5.7" {
+		t.Fatalf("init containers = %+v, want the model pull", pod.InitContainers)
+	}
+	if !hasEnv(pod.InitContainers[0].Env, "OLLAMA_MODEL") || pod.InitContainers[0].VolumeMounts[0].Name != appv1.ModelsVolumeName {
+		t.Errorf("pull container = %+v, want the model and its volume", pod.InitContainers[0])
+	}
+	if !equality.Semantic.DeepEqual(pod.InitContainers[0].Resources, pod.Containers[0].Resources) {
+		t.Errorf("pull container resources = %+v, want the ones of the app container", pod.InitContainers[0].Resources)
+	}
+	if len(pod.Volumes) != 1 || pod.Volumes[0].PersistentVolumeClaim == nil || pod.Volumes[0].PersistentVolumeClaim.ClaimName != "web-models" {
+		t.Errorf("volumes = %+v, want the PersistentVolumeClaim web-models", pod.Volumes)
+	}
+	container := pod.Containers[0]
+	for _, name := range []string{"OLLAMA_HOST", "OLLAMA_MODELS", "OLLAMA_KEEP_ALIVE"} {
+		if !hasEnv(container.Env, name) {
+			t.Errorf("env %s not set", name)
+		}
+	}
+	if probe := container.ReadinessProbe; probe == nil || probe.Exec == nil || probe.Exec.Command[2] != "qwen2.5:0.5b" {
+		t.Errorf("readiness probe = %+v, want the model run", probe)
+	}
+	if gpu := container.Resources.Limits["nvidia.com/gpu"]; gpu.Value() != 1 {
+		t.Errorf("resources = %+v, want the GPU profile", container.Resources)
+	}
+	if dep.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType {
+		t.Errorf("strategy = %s, want Recreate with a ReadWriteOnce cache", dep.Spec.Strategy.Type)
+	}
+
+	got := &appv1.App{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	if status := got.Status.Inference; status == nil || status.Endpoint != "http://web-svc.default.svc:11434/v1" {
+		t.Errorf("status.inference = %+v", status)
+	}
+
+	// Without the cache, each pod pulls the model into an emptyDir
+	got.Spec.Inference.Cache = nil
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, pvcKey, pvc); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(PersistentVolumeClaim) = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatal(err)
+	}
+	if dep.Spec.Template.Spec.Volumes[0].EmptyDir == nil || dep.Spec.Strategy.Type != appsv1.RollingUpdateDeploymentStrategyType {
+		t.Errorf("volumes = %+v, strategy %s, want an emptyDir rolled out", dep.Spec.Template.Spec.Volumes, dep.Spec.Strategy.Type)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileIngress creates the Ingress requested by spec.ingress, or deletes
+// it once spec.ingress is removed
+func (r *AppReconciler) reconcileIngress(ctx context.Context, app *appv1.App) error {
+	ing := r.desiredIngress(app)
+	if ing == nil {
+		return r.deleteOwned(ctx, app, &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+			Name:      app.IngressName(),
+			Namespace: app.Namespace,
+		}})
+	}
+
+	desired := ing.DeepCopy()
+	op, err := r.createOrUpdate(ctx, app, ing, func() error {
+		for k, v := range desired.Labels {
+			metav1.SetMetaDataLabel(&ing.ObjectMeta, k, v)
+		}
+		for k, v := range desired.Annotations {
+			metav1.SetMetaDataAnnotation(&ing.ObjectMeta, k, v)
+		}
+		ing.Spec = desired.Spec
+		return ctrl.SetControllerReference(app, ing, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Ingress reconciled", "operation", op, "name", ing.Name)
+	return nil
+}
+
+// desiredIngress returns the Ingress of the App, or nil when spec.ingress is not set
+func (r *AppReconciler) desiredIngress(app *appv1.App) *networkingv1.Ingress {
+	spec := app.Spec.Ingress
+	if spec == nil {
+		return nil
+	}
+
+	path := spec.Path
+	if path == "" {
+		path = "/"
+	}
+	port := spec.Port
+	if port == "" {
+		port = servicePorts(app)[0].Name
+	}
+	pathType := networkingv1.PathTypePrefix
+
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        app.IngressName(),
+			Namespace:   app.Namespace,
+			Labels:      app.SelectorLabels(),
+			Annotations: spec.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: spec.ClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: spec.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     path,
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: app.ServiceName(),
+							Port: networkingv1.ServiceBackendPort{Name: port},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+
+	if app.Spec.TLS != nil {
+		ing.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      tlsDNSNames(app),
+			SecretName: app.CertificateName(),
+		}}
+	}
+
+	ctrl.SetControllerReference(app, ing, r.Scheme)
+	return ing
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// setKstatusConditions sets the Ready, Reconciling and Stalled conditions read
+// by GitOps tools from the phase of the App and the rollout of its Deployment.
+// It expects the phase of the status to be set.
+func setKstatusConditions(app *appv1.App, dep *appsv1.Deployment) {
+	ready := metav1.Condition{Type: appv1.ConditionReady, Status: metav1.ConditionFalse}
+	reconciling := metav1.Condition{Type: appv1.ConditionReconciling, Status: metav1.ConditionFalse}
+	stalled := metav1.Condition{Type: appv1.ConditionStalled, Status: metav1.ConditionFalse, Reason: "Progressing"}
+
+	reason, message, rolledOut := rolloutStatus(app, dep)
+	switch {
+	case app.Status.Phase == "Failed":
+		// The spec is invalid or the policy enforced
+		stalled.Status, stalled.Reason = metav1.ConditionTrue, "Failed"
+		if c := meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionSpecValid); c != nil && c.Status == metav1.ConditionFalse {
+			stalled.Reason, stalled.Message = c.Reason, c.Message
+		} else if c := meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionPolicyViolation); c != nil {
+			stalled.Reason, stalled.Message = c.Reason, c.Message
+		}
+		ready.Reason, ready.Message = stalled.Reason, stalled.Message
+		reconciling.Reason = stalled.Reason
+	case reason == "ProgressDeadlineExceeded":
+		stalled.Status, stalled.Reason, stalled.Message = metav1.ConditionTrue, reason, message
+		ready.Reason, ready.Message = reason, message
+		reconciling.Reason = reason
+	case app.Spec.Paused:
+		// Nothing is reconciled until the App is resumed
+		ready.Reason, ready.Message = "Paused", message
+		if rolledOut {
+			ready.Status = metav1.ConditionTrue
+		}
+		reconciling.Reason, reconciling.Message = "Paused", "Owned objects are left untouched until spec.paused is unset"
+	case !rolledOut:
+		ready.Reason, ready.Message = reason, message
+		reconciling.Status, reconciling.Reason, reconciling.Message = metav1.ConditionTrue, reason, message
+	default:
+		ready.Status, ready.Reason, ready.Message = metav1.ConditionTrue, reason, message
+		reconciling.Reason = reason
+	}
+	for _, c := range []metav1.Condition{ready, reconciling, stalled} {
+		c.ObservedGeneration = app.Generation
+		meta.SetStatusCondition(&app.Status.Conditions, c)
+	}
+}
+
+// rolloutStatus returns a reason and a message describing the rollout of the
+// Deployment and whether it runs the current pod template with all its pods ready
+func rolloutStatus(app *appv1.App, dep *appsv1.Deployment) (string, string, bool) {
+	if dep.UID == "" {
+		return "DeploymentNotFound", "Deployment " + app.DeploymentName() + " is not created yet", false
+	}
+	if dep.Status.ObservedGeneration < dep.Generation {
+		return "DeploymentNotObserved", "Deployment " + dep.Name + " is not observed by its controller yet", false
+	}
+	for _, c := range dep.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			return c.Reason, c.Message, false
+		}
+	}
+	replicas := ptr.Deref(dep.Spec.Replicas, 1)
+	switch {
+	case dep.Status.UpdatedReplicas < replicas:
+		return "RollingOut", fmt.Sprintf("%d of %d pods updated", dep.Status.UpdatedReplicas, replicas), false
+	case dep.Status.Replicas > dep.Status.UpdatedReplicas:
+		return "RollingOut", fmt.Sprintf("%d old pods terminating", dep.Status.Replicas-dep.Status.UpdatedReplicas), false
+	case dep.Status.AvailableReplicas < replicas:
+		return "WaitingForPods", fmt.Sprintf("%d of %d pods available", dep.Status.AvailableReplicas, replicas), false
+	}
+	return "Available", fmt.Sprintf("%d of %d pods available", dep.Status.AvailableReplicas, replicas), true
+}
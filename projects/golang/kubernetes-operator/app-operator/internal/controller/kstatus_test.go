@@ -0,0 +1,185 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestSetKstatusConditions(t *testing.T) {
+	deployment := func(mutate func(*appsv1.Deployment)) *appsv1.Deployment {
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-app", UID: "1", Generation: 4},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 4, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2,
+			},
+		}
+		if mutate != nil {
+			mutate(dep)
+		}
+		return dep
+	}
+	tests := []struct {
+		name   string
+		app    func(*appv1.App)
+		dep    *appsv1.Deployment
+		reason string
+		want   string
+	}{
+		{
+			name:   "available",
+			dep:    deployment(nil),
+			reason: "Available",
+			want:   "Healthy",
+		},
+		{
+			name:   "deployment not created",
+			dep:    &appsv1.Deployment{},
+			reason: "DeploymentNotFound",
+			want:   "Progressing",
+		},
+		{
+			name:   "deployment not observed",
+			dep:    deployment(func(d *appsv1.Deployment) { d.Generation = 5 }),
+			reason: "DeploymentNotObserved",
+			want:   "Progressing",
+		},
+		{
+			name:   "rolling out",
+			dep:    deployment(func(d *appsv1.Deployment) { d.Status.Replicas, d.Status.UpdatedReplicas = 3, 1 }),
+			reason: "RollingOut",
+			want:   "Progressing",
+		},
+		{
+			name:   "old pods terminating",
+			dep:    deployment(func(d *appsv1.Deployment) { d.Status.Replicas = 3 }),
+			reason: "RollingOut",
+			want:   "Progressing",
+		},
+		{
+			name:   "pods not available",
+			dep:    deployment(func(d *appsv1.Deployment) { d.Status.AvailableReplicas = 1 }),
+			reason: "WaitingForPods",
+			want:   "Progressing",
+		},
+		{
+			name: "progress deadline exceeded",
+			dep: deployment(func(d *appsv1.Deployment) {
+				d.Status.UpdatedReplicas = 1
+				d.Status.Conditions = []appsv1.DeploymentCondition{{
+					Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+				}}
+			}),
+			reason: "ProgressDeadlineExceeded",
+			want:   "Degraded",
+		},
+		{
+			name: "invalid spec",
+			app: func(a *appv1.App) {
+				a.Status.Phase = "Failed"
+				meta.SetStatusCondition(&a.Status.Conditions, metav1.Condition{
+					Type: appv1.ConditionSpecValid, Status: metav1.ConditionFalse, Reason: "InvalidSpec",
+				})
+			},
+			dep:    deployment(nil),
+			reason: "InvalidSpec",
+			want:   "Degraded",
+		},
+		{
+			name: "policy violated",
+			app: func(a *appv1.App) {
+				a.Status.Phase = "Failed"
+				meta.SetStatusCondition(&a.Status.Conditions, metav1.Condition{
+					Type: appv1.ConditionPolicyViolation, Status: metav1.ConditionTrue, Reason: "PolicyViolated",
+				})
+			},
+			dep:    &appsv1.Deployment{},
+			reason: "PolicyViolated",
+			want:   "Degraded",
+		},
+		{
+			name:   "paused",
+			app:    func(a *appv1.App) { a.Spec.Paused = true },
+			dep:    deployment(nil),
+			reason: "Paused",
+			want:   "Healthy",
+		},
+		{
+			// The Ready condition stays False until the rollout completes
+			name:   "paused during a rollout",
+			app:    func(a *appv1.App) { a.Spec.Paused = true },
+			dep:    deployment(func(d *appsv1.Deployment) { d.Status.UpdatedReplicas = 1 }),
+			reason: "Paused",
+			want:   "Progressing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(func(a *appv1.App) {
+				a.Generation = 2
+				a.Status.ObservedGeneration = 2
+				a.Status.Phase = "Running"
+				if tt.app != nil {
+					tt.app(a)
+				}
+			})
+			setKstatusConditions(app, tt.dep)
+
+			ready := meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionReady)
+			if ready == nil || ready.Reason != tt.reason || ready.ObservedGeneration != 2 {
+				t.Errorf("Ready condition = %+v, want reason %s", ready, tt.reason)
+			}
+			for _, condType := range []string{appv1.ConditionReady, appv1.ConditionReconciling, appv1.ConditionStalled} {
+				if c := meta.FindStatusCondition(app.Status.Conditions, condType); c == nil || c.Reason == "" {
+					t.Errorf("condition %s = %+v, want a reason", condType, c)
+				}
+			}
+
+			if got := healthStatus(app); got != tt.want {
+				t.Errorf("health = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// healthStatus returns the health of an App as kstatus.HealthScript computes
+// it for Argo CD, in the same order
+func healthStatus(app *appv1.App) string {
+	switch {
+	case app.Status.ObservedGeneration != app.Generation:
+		return "Progressing"
+	case meta.IsStatusConditionTrue(app.Status.Conditions, appv1.ConditionPaused):
+		return "Suspended"
+	case meta.IsStatusConditionTrue(app.Status.Conditions, appv1.ConditionStalled):
+		return "Degraded"
+	case meta.IsStatusConditionTrue(app.Status.Conditions, appv1.ConditionReconciling):
+		return "Progressing"
+	case meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionReady) != nil &&
+		!meta.IsStatusConditionTrue(app.Status.Conditions, appv1.ConditionReady):
+		return "Progressing"
+	}
+	return "Healthy"
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+var _ = Describe("Leader election handover", func() {
+	It("drains the reconciles in flight before releasing the lease", func() {
+		leaderElectionID := "handover-" + rand.String(5)
+		newManager := func() manager.Manager {
+			mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+				Scheme:                        scheme.Scheme,
+				Metrics:                       metricsserver.Options{BindAddress: "0"},
+				LeaderElection:                true,
+				LeaderElectionID:              leaderElectionID,
+				LeaderElectionNamespace:       "default",
+				LeaderElectionReleaseOnCancel: true,
+				// Without the release, the second manager would wait for the whole lease
+				LeaseDuration:           ptr.To(time.Minute),
+				RenewDeadline:           ptr.To(30 * time.Second),
+				RetryPeriod:             ptr.To(200 * time.Millisecond),
+				GracefulShutdownTimeout: ptr.To(time.Minute),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return mgr
+		}
+		app := &appv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: leaderElectionID, Namespace: "default"},
+			Spec:       appv1.AppSpec{Image: "nginx:1.27"},
+		}
+
+		By("electing the first manager and starting a reconcile")
+		started := make(chan struct{})
+		release := make(chan struct{})
+		reconciled := make(chan error, 1)
+		first := newManager()
+		Expect(ctrl.NewControllerManagedBy(first).
+			Named("handover-first").
+			For(&appv1.App{}).
+			Complete(drain(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				if req.Name != app.Name {
+					return reconcile.Result{}, nil
+				}
+				close(started)
+				<-release
+				// An API call once the manager is stopped
+				reconciled <- k8sClient.Get(ctx, req.NamespacedName, &appv1.App{})
+				return reconcile.Result{}, nil
+			}), 50*time.Second))).To(Succeed())
+
+		firstCtx, stopFirst := context.WithCancel(context.Background())
+		defer stopFirst()
+		firstDone := make(chan error, 1)
+		go func() { firstDone <- first.Start(firstCtx) }()
+		Eventually(first.Elected(), 10*time.Second).Should(BeClosed())
+
+		Expect(k8sClient.Create(context.Background(), app)).To(Succeed())
+		defer func() { _ = k8sClient.Delete(context.Background(), app) }()
+		Eventually(started, 10*time.Second).Should(BeClosed())
+
+		By("starting the second manager as a candidate")
+		second := newManager()
+		Expect(ctrl.NewControllerManagedBy(second).
+			Named("handover-second").
+			For(&appv1.App{}).
+			Complete(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			}))).To(Succeed())
+		secondCtx, stopSecond := context.WithCancel(context.Background())
+		defer stopSecond()
+		go func() { _ = second.Start(secondCtx) }()
+		Consistently(second.Elected(), time.Second).ShouldNot(BeClosed())
+
+		By("stopping the first manager while the reconcile is in flight")
+		stopFirst()
+		Consistently(second.Elected(), 2*time.Second).ShouldNot(BeClosed())
+
+		By("releasing the lease once the reconcile completes")
+		close(release)
+		Eventually(reconciled).Should(Receive(Succeed()))
+		Eventually(firstDone, 10*time.Second).Should(Receive(Succeed()))
+		Eventually(second.Elected(), 10*time.Second).Should(BeClosed())
+	})
+})
@@ -0,0 +1,243 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+const (
+	// istioInjectLabel is the pod label read by the Istio sidecar injector
+	istioInjectLabel = "sidecar.istio.io/inject"
+	// linkerdInjectAnnotation is the pod annotation read by the Linkerd proxy injector
+	linkerdInjectAnnotation = "linkerd.io/inject"
+)
+
+// +kubebuilder:rbac:groups=networking.istio.io,resources=virtualservices;destinationrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=security.istio.io,resources=peerauthentications,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileMesh applies the traffic policy of spec.mesh with the Istio
+// VirtualService, DestinationRule and PeerAuthentication of the Service, and
+// removes the ones that are no longer wanted. Without Istio, only the sidecar injection applies.
+func (r *AppReconciler) reconcileMesh(ctx context.Context, app *appv1.App) error {
+	spec := app.Spec.Mesh
+	if r.APIs.Istio {
+		for _, obj := range []struct {
+			gvk     schema.GroupVersionKind
+			desired *unstructured.Unstructured
+		}{
+			{virtualServiceGVK, desiredVirtualService(app)},
+			{destinationRuleGVK, desiredDestinationRule(app)},
+			{peerAuthenticationGVK, desiredPeerAuthentication(app)},
+		} {
+			if obj.desired == nil {
+				if err := r.deleteOwned(ctx, app, newUnstructured(obj.gvk, app.Namespace, app.ServiceName())); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := r.applyUnstructured(ctx, app, obj.desired); err != nil {
+				return err
+			}
+		}
+	}
+
+	if spec == nil {
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionMesh)
+		return nil
+	}
+	cond := metav1.Condition{
+		Type:               appv1.ConditionMesh,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reconciled",
+		Message:            "The sidecar injection is requested from " + string(meshProvider(spec)),
+		ObservedGeneration: app.Generation,
+	}
+	switch {
+	case meshProvider(spec) != appv1.MeshProviderIstio && spec.HasTrafficPolicy():
+		cond.Status, cond.Reason = metav1.ConditionFalse, "TrafficPolicyUnsupported"
+		cond.Message = "The timeout, retries and mTLS mode are applied with Istio only, the sidecar injection is requested"
+	case meshProvider(spec) == appv1.MeshProviderIstio && !r.APIs.Istio:
+		cond.Status, cond.Reason = metav1.ConditionFalse, "IstioNotInstalled"
+		cond.Message = "The networking.istio.io/v1beta1 and security.istio.io/v1beta1 APIs are not served by the cluster"
+	case spec.HasTrafficPolicy():
+		cond.Message = "The traffic policy is applied to Service " + app.ServiceName()
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
+	return nil
+}
+
+// applyUnstructured creates or updates a third-party object owned by the App,
+// replacing its spec and adding its labels. It returns the live object.
+func (r *AppReconciler) applyUnstructured(ctx context.Context, app *appv1.App, desired *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	obj := newUnstructured(desired.GroupVersionKind(), desired.GetNamespace(), desired.GetName())
+	op, err := r.createOrUpdate(ctx, app, obj, func() error {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range desired.GetLabels() {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+		if err := unstructured.SetNestedField(obj.Object, desired.Object["spec"], "spec"); err != nil {
+			return err
+		}
+		return ctrl.SetControllerReference(app, obj, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("Object reconciled", "operation", op, "kind", obj.GetKind(), "name", obj.GetName())
+	return obj, nil
+}
+
+// desiredVirtualService returns the VirtualService applying the timeout and
+// retries of spec.mesh, or nil when Istio routes with its defaults
+func desiredVirtualService(app *appv1.App) *unstructured.Unstructured {
+	spec := app.Spec.Mesh
+	if spec == nil || meshProvider(spec) != appv1.MeshProviderIstio || (spec.Timeout == nil && spec.Retries == nil) {
+		return nil
+	}
+
+	// Short hosts are resolved in the namespace of the VirtualService
+	host := app.ServiceName()
+	route := map[string]interface{}{
+		"route": []interface{}{
+			map[string]interface{}{"destination": map[string]interface{}{"host": host}},
+		},
+	}
+	if spec.Timeout != nil {
+		route["timeout"] = istioDuration(spec.Timeout.Duration)
+	}
+	if retries := spec.Retries; retries != nil {
+		policy := map[string]interface{}{"attempts": int64(retries.Attempts)}
+		if retries.PerTryTimeout != nil {
+			policy["perTryTimeout"] = istioDuration(retries.PerTryTimeout.Duration)
+		}
+		if retries.RetryOn != "" {
+			policy["retryOn"] = retries.RetryOn
+		}
+		route["retries"] = policy
+	}
+
+	vs := newUnstructured(virtualServiceGVK, app.Namespace, app.ServiceName())
+	vs.SetLabels(app.SelectorLabels())
+	vs.Object["spec"] = map[string]interface{}{
+		"hosts": []interface{}{host},
+		"http":  []interface{}{route},
+	}
+	return vs
+}
+
+// desiredDestinationRule returns the DestinationRule applying the mTLS mode
+// of spec.mesh to the clients, or nil when the mesh-wide setting applies
+func desiredDestinationRule(app *appv1.App) *unstructured.Unstructured {
+	spec := app.Spec.Mesh
+	if spec == nil || meshProvider(spec) != appv1.MeshProviderIstio || spec.MTLS == "" {
+		return nil
+	}
+
+	mode := "ISTIO_MUTUAL"
+	if spec.MTLS == appv1.MTLSModeDisable {
+		mode = "DISABLE"
+	}
+	dr := newUnstructured(destinationRuleGVK, app.Namespace, app.ServiceName())
+	dr.SetLabels(app.SelectorLabels())
+	dr.Object["spec"] = map[string]interface{}{
+		"host": app.ServiceName(),
+		"trafficPolicy": map[string]interface{}{
+			"tls": map[string]interface{}{"mode": mode},
+		},
+	}
+	return dr
+}
+
+// desiredPeerAuthentication returns the PeerAuthentication applying the mTLS
+// mode of spec.mesh to the pods, or nil when the mesh-wide setting applies.
+// The DestinationRule only sets what the clients in the mesh send, Strict
+// also needs the pods to refuse plain text.
+func desiredPeerAuthentication(app *appv1.App) *unstructured.Unstructured {
+	spec := app.Spec.Mesh
+	if spec == nil || meshProvider(spec) != appv1.MeshProviderIstio || spec.MTLS == "" {
+		return nil
+	}
+
+	mode := "STRICT"
+	if spec.MTLS == appv1.MTLSModeDisable {
+		mode = "DISABLE"
+	}
+	matchLabels := map[string]interface{}{}
+	for k, v := range app.SelectorLabels() {
+		matchLabels[k] = v
+	}
+	pa := newUnstructured(peerAuthenticationGVK, app.Namespace, app.ServiceName())
+	pa.SetLabels(app.SelectorLabels())
+	pa.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": matchLabels},
+		"mtls":     map[string]interface{}{"mode": mode},
+	}
+	return pa
+}
+
+// meshPodLabels returns the pod labels requesting the Istio sidecar
+func meshPodLabels(app *appv1.App) map[string]string {
+	spec := app.Spec.Mesh
+	if spec == nil || meshProvider(spec) != appv1.MeshProviderIstio {
+		return nil
+	}
+	return map[string]string{istioInjectLabel: strconv.FormatBool(ptr.Deref(spec.Inject, true))}
+}
+
+// meshPodAnnotations returns the pod annotations requesting the Linkerd proxy
+func meshPodAnnotations(app *appv1.App) map[string]string {
+	spec := app.Spec.Mesh
+	if spec == nil || meshProvider(spec) != appv1.MeshProviderLinkerd {
+		return nil
+	}
+	inject := "enabled"
+	if !ptr.Deref(spec.Inject, true) {
+		inject = "disabled"
+	}
+	return map[string]string{linkerdInjectAnnotation: inject}
+}
+
+// meshProvider returns the provider of spec.mesh, Istio by default
+func meshProvider(spec *appv1.MeshSpec) appv1.MeshProvider {
+	if spec.Provider == "" {
+		return appv1.MeshProviderIstio
+	}
+	return spec.Provider
+}
+
+// istioDuration formats a duration as the protobuf JSON durations of the Istio
+// APIs, in seconds, e.g. 90s rather than 1m30s
+func istioDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
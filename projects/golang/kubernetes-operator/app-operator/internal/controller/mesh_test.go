@@ -0,0 +1,159 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestDesiredVirtualService(t *testing.T) {
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Mesh = &appv1.MeshSpec{
+			Timeout: &metav1.Duration{Duration: 90 * time.Second},
+			Retries: &appv1.MeshRetries{
+				Attempts:      3,
+				PerTryTimeout: &metav1.Duration{Duration: 1500 * time.Millisecond},
+				RetryOn:       "5xx,connect-failure",
+			},
+		}
+	})
+	want := map[string]interface{}{
+		"hosts": []interface{}{"web-svc"},
+		"http": []interface{}{map[string]interface{}{
+			"route":   []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "web-svc"}}},
+			"timeout": "90s",
+			"retries": map[string]interface{}{"attempts": int64(3), "perTryTimeout": "1.5s", "retryOn": "5xx,connect-failure"},
+		}},
+	}
+	vs := desiredVirtualService(app)
+	if diff := cmp.Diff(want, vs.Object["spec"]); diff != "" {
+		t.Errorf("spec mismatch (-want +got):\n%s", diff)
+	}
+	if desiredDestinationRule(app) != nil {
+		t.Error("DestinationRule without spec.mesh.mtls")
+	}
+
+	app.Spec.Mesh.Provider = appv1.MeshProviderLinkerd
+	if desiredVirtualService(app) != nil {
+		t.Error("VirtualService with Linkerd")
+	}
+}
+
+func TestReconcileMesh(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	for _, gvk := range []schema.GroupVersionKind{virtualServiceGVK, destinationRuleGVK, peerAuthenticationGVK} {
+		scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+	}
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Mesh = &appv1.MeshSpec{MTLS: appv1.MTLSModeStrict}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{Client: c, Scheme: scheme, APIs: AvailableAPIs{Istio: true}}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionMesh, "Reconciled")
+	dr := newUnstructured(destinationRuleGVK, "default", "web-svc")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dr), dr); err != nil {
+		t.Fatal(err)
+	}
+	if mode, _, _ := unstructured.NestedString(dr.Object, "spec", "trafficPolicy", "tls", "mode"); mode != "ISTIO_MUTUAL" {
+		t.Errorf("tls mode = %q, want ISTIO_MUTUAL", mode)
+	}
+	pa := newUnstructured(peerAuthenticationGVK, "default", "web-svc")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pa), pa); err != nil {
+		t.Fatal(err)
+	}
+	if mode, _, _ := unstructured.NestedString(pa.Object, "spec", "mtls", "mode"); mode != "STRICT" {
+		t.Errorf("mtls mode = %q, want STRICT", mode)
+	}
+	selector, _, _ := unstructured.NestedStringMap(pa.Object, "spec", "selector", "matchLabels")
+	if diff := cmp.Diff(app.SelectorLabels(), selector); diff != "" {
+		t.Errorf("selector mismatch (-want +got):\n%s", diff)
+	}
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	if got := dep.Spec.Template.Labels[istioInjectLabel]; got != "true" {
+		t.Errorf("pod label %s = %q, want true", istioInjectLabel, got)
+	}
+
+	// Switching to Linkerd moves the injection to an annotation and drops the traffic policy
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Mesh = &appv1.MeshSpec{Provider: appv1.MeshProviderLinkerd, Inject: ptr.To(true), MTLS: appv1.MTLSModeStrict}
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionMesh, "TrafficPolicyUnsupported")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dr), dr); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(DestinationRule) = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pa), pa); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(PeerAuthentication) = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dep.Spec.Template.Labels[istioInjectLabel]; ok {
+		t.Errorf("pod label %s kept", istioInjectLabel)
+	}
+	if got := dep.Spec.Template.Annotations[linkerdInjectAnnotation]; got != "enabled" {
+		t.Errorf("pod annotation %s = %q, want enabled", linkerdInjectAnnotation, got)
+	}
+
+	// Without Istio, the App is reconciled and the condition explains why the policy is not applied
+	r.APIs.Istio = false
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Mesh = &appv1.MeshSpec{Timeout: &metav1.Duration{Duration: time.Second}}
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionMesh, "IstioNotInstalled")
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileMonitoring creates the ServiceMonitor or PodMonitor requested by
+// spec.monitoring and removes the ones that are no longer wanted.
+func (r *AppReconciler) reconcileMonitoring(ctx context.Context, app *appv1.App) error {
+	log := log.FromContext(ctx)
+	spec := app.Spec.Monitoring
+
+	if !r.APIs.PrometheusOperator {
+		if spec == nil {
+			meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionMonitoring)
+			return nil
+		}
+		meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               appv1.ConditionMonitoring,
+			Status:             metav1.ConditionFalse,
+			Reason:             "PrometheusOperatorNotInstalled",
+			Message:            "The monitoring.coreos.com/v1 API is not served by the cluster",
+			ObservedGeneration: app.Generation,
+		})
+		return nil
+	}
+
+	desired := r.desiredMonitor(app)
+	for _, gvk := range []schema.GroupVersionKind{serviceMonitorGVK, podMonitorGVK} {
+		if desired != nil && desired.GroupVersionKind() == gvk {
+			continue
+		}
+		if err := r.deleteOwned(ctx, app, newUnstructured(gvk, app.Namespace, app.MonitorName())); err != nil {
+			return err
+		}
+	}
+	if desired == nil {
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionMonitoring)
+		return nil
+	}
+
+	monitor := newUnstructured(desired.GroupVersionKind(), desired.GetNamespace(), desired.GetName())
+	op, err := r.createOrUpdate(ctx, app, monitor, func() error {
+		labels := monitor.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range desired.GetLabels() {
+			labels[k] = v
+		}
+		monitor.SetLabels(labels)
+		if err := unstructured.SetNestedField(monitor.Object, desired.Object["spec"], "spec"); err != nil {
+			return err
+		}
+		return ctrl.SetControllerReference(app, monitor, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Monitor reconciled", "operation", op, "kind", monitor.GetKind(), "name", monitor.GetName())
+
+	meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+		Type:               appv1.ConditionMonitoring,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Reconciled",
+		Message:            monitor.GetKind() + " " + monitor.GetName() + " is reconciled",
+		ObservedGeneration: app.Generation,
+	})
+	return nil
+}
+
+// desiredMonitor returns the ServiceMonitor or PodMonitor of the App,
+// or nil when monitoring is disabled
+func (r *AppReconciler) desiredMonitor(app *appv1.App) *unstructured.Unstructured {
+	spec := app.Spec.Monitoring
+	if spec == nil {
+		return nil
+	}
+
+	endpoint := map[string]interface{}{
+		"port": spec.Port,
+		"path": spec.Path,
+	}
+	if spec.Port == "" {
+		endpoint["port"] = "http"
+	}
+	if spec.Path == "" {
+		endpoint["path"] = "/metrics"
+	}
+	if spec.Interval != "" {
+		endpoint["interval"] = spec.Interval
+	}
+
+	selector := map[string]interface{}{}
+	for k, v := range app.SelectorLabels() {
+		selector[k] = v
+	}
+
+	gvk, endpointsField := serviceMonitorGVK, "endpoints"
+	if spec.Kind == appv1.MonitorKindPodMonitor {
+		gvk, endpointsField = podMonitorGVK, "podMetricsEndpoints"
+	}
+	monitor := newUnstructured(gvk, app.Namespace, app.MonitorName())
+	monitor.Object["spec"] = map[string]interface{}{
+		"selector":     map[string]interface{}{"matchLabels": selector},
+		endpointsField: []interface{}{endpoint},
+	}
+
+	labels := app.SelectorLabels()
+	for k, v := range r.MonitorLabels {
+		labels[k] = v
+	}
+	for k, v := range spec.Labels {
+		labels[k] = v
+	}
+	monitor.SetLabels(labels)
+	return monitor
+}
+
+// deleteOwned deletes obj when it exists and is controlled by the App
+func (r *AppReconciler) deleteOwned(ctx context.Context, app *appv1.App, obj client.Object) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(obj, app) {
+		return nil
+	}
+	log.FromContext(ctx).Info("Deleting object no longer needed", "kind", obj.GetObjectKind().GroupVersionKind().Kind, "name", obj.GetName())
+	return client.IgnoreNotFound(r.deleteObject(ctx, app, obj))
+}
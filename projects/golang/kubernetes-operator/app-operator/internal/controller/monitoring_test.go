@@ -0,0 +1,134 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestReconcileMonitoring(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	scheme.AddKnownTypeWithName(serviceMonitorGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(podMonitorGVK, &unstructured.Unstructured{})
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Monitoring = &appv1.MonitoringSpec{
+			Interval: "30s",
+			Labels:   map[string]string{"team": "shop", "release": "app"},
+		}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{
+		Client:        c,
+		Scheme:        scheme,
+		APIs:          AvailableAPIs{PrometheusOperator: true},
+		MonitorLabels: map[string]string{"release": "prometheus", "scrape": "true"},
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionMonitoring, "Reconciled")
+	sm := newUnstructured(serviceMonitorGVK, "default", "web-monitor")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(sm), sm); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		"endpoints": []interface{}{
+			map[string]interface{}{"port": "http", "path": "/metrics", "interval": "30s"},
+		},
+	}
+	if diff := cmp.Diff(want, sm.Object["spec"]); diff != "" {
+		t.Errorf("ServiceMonitor spec mismatch (-want +got):\n%s", diff)
+	}
+	// The labels of the App win over --monitor-labels
+	wantLabels := map[string]string{"app": "web", "release": "app", "scrape": "true", "team": "shop"}
+	if diff := cmp.Diff(wantLabels, sm.GetLabels()); diff != "" {
+		t.Errorf("ServiceMonitor labels mismatch (-want +got):\n%s", diff)
+	}
+	if refs := sm.GetOwnerReferences(); len(refs) != 1 || refs[0].UID != app.UID {
+		t.Errorf("owner references = %+v, want the App", refs)
+	}
+
+	// Switching to a PodMonitor deletes the ServiceMonitor
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Monitoring.Kind = appv1.MonitorKindPodMonitor
+	app.Spec.Monitoring.Port = "metrics"
+	app.Spec.Monitoring.Path = "/stats"
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(sm), sm); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(ServiceMonitor) = %v, want NotFound", err)
+	}
+	pm := newUnstructured(podMonitorGVK, "default", "web-monitor")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pm), pm); err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		"podMetricsEndpoints": []interface{}{
+			map[string]interface{}{"port": "metrics", "path": "/stats", "interval": "30s"},
+		},
+	}
+	if diff := cmp.Diff(want, pm.Object["spec"]); diff != "" {
+		t.Errorf("PodMonitor spec mismatch (-want +got):\n%s", diff)
+	}
+
+	// Removing spec.monitoring deletes the PodMonitor
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.Monitoring = nil
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pm), pm); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(PodMonitor) = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	if cond := meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionMonitoring); cond != nil {
+		t.Errorf("Monitoring condition = %+v without spec.monitoring", cond)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/policy"
+)
+
+// setPolicyCondition reports the violations of the policy in the
+// PolicyViolation condition and returns whether the App may be reconciled,
+// i.e. it follows the policy or the policy is only audited.
+// Apps created before the webhook was enabled are caught here.
+func (r *AppReconciler) setPolicyCondition(app *appv1.App) bool {
+	if !r.Policy.Enabled() {
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionPolicyViolation)
+		return true
+	}
+	cond := metav1.Condition{
+		Type:               appv1.ConditionPolicyViolation,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Compliant",
+		Message:            "The App follows the policy",
+		ObservedGeneration: app.Generation,
+	}
+	errs := r.Policy.Check(app)
+	if len(errs) > 0 {
+		cond.Status, cond.Reason = metav1.ConditionTrue, "PolicyViolated"
+		cond.Message = errs.ToAggregate().Error()
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
+	return len(errs) == 0 || r.Policy.Action == policy.Audit
+}
+
+// podLabels are the labels of the pods: the selector labels, the sidecar
+// injection label of the mesh and the labels required by the policy, so that
+// the pods pass the equivalent pod policies
+func (r *AppReconciler) podLabels(app *appv1.App) map[string]string {
+	labels := app.SelectorLabels()
+	for k, v := range meshPodLabels(app) {
+		labels[k] = v
+	}
+	for _, l := range r.Policy.RequiredLabels {
+		if v := app.Labels[l]; v != "" {
+			if _, ok := labels[l]; !ok {
+				labels[l] = v
+			}
+		}
+	}
+	return labels
+}
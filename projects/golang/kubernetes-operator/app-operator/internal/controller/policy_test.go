@@ -0,0 +1,100 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/policy"
+)
+
+func TestReconcilePolicy(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(nil)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{Client: c, Scheme: scheme, Policy: policy.Policy{
+		Action:         policy.Enforce,
+		RequiredLabels: []string{"team"},
+	}}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	// Enforced: nothing is applied
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionPolicyViolation, "PolicyViolated")
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: app.DeploymentName()}, dep); !apierrors.IsNotFound(err) {
+		t.Fatalf("Get(Deployment) = %v, want NotFound", err)
+	}
+
+	// Audited: the App is reconciled with the violation reported
+	r.Policy.Action = policy.Audit
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionPolicyViolation, "PolicyViolated")
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: app.DeploymentName()}, dep); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fixed: the required label is copied to the pods
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Labels = map[string]string{"team": "shop"}
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	r.Policy.Action = policy.Enforce
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionPolicyViolation, "Compliant")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatal(err)
+	}
+	if got := dep.Spec.Template.Labels["team"]; got != "shop" {
+		t.Errorf("pod label team = %q, want shop", got)
+	}
+
+	// Disabled: the condition is removed
+	r.Policy = policy.Policy{}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	if meta.FindStatusCondition(app.Status.Conditions, appv1.ConditionPolicyViolation) != nil {
+		t.Error("PolicyViolation condition set without policy")
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// secretsHashAnnotation on the pod template rolls the pods when the values
+// of spec.secretsFrom change, since envFrom is only read at container start
+const secretsHashAnnotation = "apps.test.local/secrets-hash"
+
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileSecrets materializes spec.secretsFrom into the Secret <app>-secrets
+// and returns the hash of its data. Failures to read the stores are reported
+// by the SecretsSynced condition and keep the last synced values.
+func (r *AppReconciler) reconcileSecrets(ctx context.Context, app *appv1.App) (string, error) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: app.SecretName(), Namespace: app.Namespace}}
+	if len(app.Spec.SecretsFrom) == 0 {
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionSecretsSynced)
+		return "", r.deleteOwned(ctx, app, secret)
+	}
+
+	data, err := r.readSecrets(ctx, app)
+	if err != nil {
+		log.FromContext(ctx).Info("Failed to read secrets", "error", err.Error())
+		meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               appv1.ConditionSecretsSynced,
+			Status:             metav1.ConditionFalse,
+			Reason:             "SyncFailed",
+			Message:            err.Error(),
+			ObservedGeneration: app.Generation,
+		})
+		if err := r.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+		return secretsHash(secret.Data), nil
+	}
+
+	op, err := r.createOrUpdate(ctx, app, secret, func() error {
+		for k, v := range app.SelectorLabels() {
+			metav1.SetMetaDataLabel(&secret.ObjectMeta, k, v)
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+		return ctrl.SetControllerReference(app, secret, r.Scheme)
+	})
+	if err != nil {
+		return "", err
+	}
+	log.FromContext(ctx).Info("Secret reconciled", "operation", op, "name", secret.Name)
+
+	meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+		Type:               appv1.ConditionSecretsSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            fmt.Sprintf("%d keys synced", len(data)),
+		ObservedGeneration: app.Generation,
+	})
+	return secretsHash(data), nil
+}
+
+// readSecrets returns the keys of spec.secretsFrom, later entries overriding earlier ones
+func (r *AppReconciler) readSecrets(ctx context.Context, app *appv1.App) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, src := range app.Spec.SecretsFrom {
+		provider, ok := r.SecretProviders[src.Provider]
+		if !ok {
+			return nil, fmt.Errorf("secret provider %s is not configured", src.Provider)
+		}
+		values, err := provider.Get(ctx, app.Namespace, src.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", src.Provider, src.Path, err)
+		}
+		if len(src.Keys) == 0 {
+			for k, v := range values {
+				data[k] = v
+			}
+			continue
+		}
+		for _, key := range src.Keys {
+			v, ok := values[key.Key]
+			if !ok {
+				return nil, fmt.Errorf("%s %s: key %s not found", src.Provider, src.Path, key.Key)
+			}
+			name := key.Env
+			if name == "" {
+				name = key.Key
+			}
+			data[name] = v
+		}
+	}
+	return data, nil
+}
+
+// containerEnvFrom returns the envFrom of the app container, exposing the
+// Secret of spec.secretsFrom. The Secret is optional, so the pods still start
+// when the first sync fails; SecretsSynced reports the failure.
+func containerEnvFrom(app *appv1.App) []corev1.EnvFromSource {
+	if len(app.Spec.SecretsFrom) == 0 {
+		return nil
+	}
+	return []corev1.EnvFromSource{{
+		SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: app.SecretName()},
+			Optional:             ptr.To(true),
+		},
+	}}
+}
+
+// secretsHash returns a stable hash of the Secret data, empty for no data
+func secretsHash(data map[string][]byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%x;", k, data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/secretstore"
+)
+
+// fakeStore serves secrets from memory, or fails with err
+type fakeStore struct {
+	secrets map[string]map[string][]byte
+	err     error
+}
+
+func (s *fakeStore) Get(_ context.Context, _, p string) (map[string][]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	data, ok := s.secrets[p]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func TestReconcileSecrets(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.SecretsFrom = []appv1.SecretSource{
+			{Provider: appv1.SecretProviderVault, Path: "web/db", Keys: []appv1.SecretKey{{Key: "password", Env: "DB_PASSWORD"}}},
+			{Provider: appv1.SecretProviderVault, Path: "web/api"},
+		}
+	})
+	store := &fakeStore{secrets: map[string]map[string][]byte{
+		"web/db":  {"password": []byte("s3cret"), "user": []byte("web")},
+		"web/api": {"API_TOKEN": []byte("t0ken")},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(&appv1.App{}).Build()
+	r := &AppReconciler{
+		Client:                 c,
+		Scheme:                 scheme,
+		SecretProviders:        map[appv1.SecretProvider]secretstore.Provider{appv1.SecretProviderVault: store},
+		SecretsRefreshInterval: time.Minute,
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != time.Minute {
+		t.Errorf("RequeueAfter = %s, want the refresh interval", res.RequeueAfter)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-secrets"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.Data) != 2 || string(secret.Data["DB_PASSWORD"]) != "s3cret" || string(secret.Data["API_TOKEN"]) != "t0ken" {
+		t.Errorf("secret data = %v", secret.Data)
+	}
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	if envFrom := dep.Spec.Template.Spec.Containers[0].EnvFrom; len(envFrom) != 1 || envFrom[0].SecretRef.Name != "web-secrets" || !ptr.Deref(envFrom[0].SecretRef.Optional, false) {
+		t.Errorf("envFrom = %v", envFrom)
+	}
+	hash := dep.Spec.Template.Annotations[secretsHashAnnotation]
+	if hash == "" {
+		t.Error("secrets hash annotation not set")
+	}
+	assertCondition(t, c, app, appv1.ConditionSecretsSynced, "Synced")
+
+	// A refreshed value rolls the pods
+	store.secrets["web/api"]["API_TOKEN"] = []byte("r0tated")
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatal(err)
+	}
+	if dep.Spec.Template.Annotations[secretsHashAnnotation] == hash {
+		t.Error("secrets hash annotation not updated")
+	}
+
+	// A failing store keeps the last synced values
+	store.err = errors.New("vault sealed")
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["API_TOKEN"]) != "r0tated" {
+		t.Errorf("secret data = %v, want the last synced values", secret.Data)
+	}
+	assertCondition(t, c, app, appv1.ConditionSecretsSynced, "SyncFailed")
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// Without escalate nor bind, the API server rejects the rules of
+// spec.serviceAccount the operator does not hold itself. The webhook rejects
+// the rules the author of the App does not hold.
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileServiceAccount creates the ServiceAccount of spec.serviceAccount
+// and the Role and RoleBinding granting its rules, or deletes them once they
+// are no longer wanted. It runs before the Deployment so that the pods find
+// their ServiceAccount.
+func (r *AppReconciler) reconcileServiceAccount(ctx context.Context, app *appv1.App) error {
+	sa, role, binding := desiredServiceAccount(app), desiredRole(app), desiredRoleBinding(app)
+	objMeta := metav1.ObjectMeta{Name: app.ServiceAccountName(), Namespace: app.Namespace}
+	if role == nil {
+		for _, obj := range []client.Object{&rbacv1.RoleBinding{ObjectMeta: objMeta}, &rbacv1.Role{ObjectMeta: objMeta}} {
+			if err := r.deleteOwned(ctx, app, obj); err != nil {
+				return err
+			}
+		}
+	}
+	if sa == nil {
+		return r.deleteOwned(ctx, app, &corev1.ServiceAccount{ObjectMeta: objMeta})
+	}
+
+	wantSA := sa.DeepCopy()
+	op, err := r.createOrUpdate(ctx, app, sa, func() error {
+		for k, v := range wantSA.Labels {
+			metav1.SetMetaDataLabel(&sa.ObjectMeta, k, v)
+		}
+		for k, v := range wantSA.Annotations {
+			metav1.SetMetaDataAnnotation(&sa.ObjectMeta, k, v)
+		}
+		return ctrl.SetControllerReference(app, sa, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("ServiceAccount reconciled", "operation", op, "name", sa.Name)
+	if role == nil {
+		return nil
+	}
+
+	wantRole := role.DeepCopy()
+	op, err = r.createOrUpdate(ctx, app, role, func() error {
+		for k, v := range wantRole.Labels {
+			metav1.SetMetaDataLabel(&role.ObjectMeta, k, v)
+		}
+		role.Rules = wantRole.Rules
+		return ctrl.SetControllerReference(app, role, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Role reconciled", "operation", op, "name", role.Name)
+
+	wantBinding := binding.DeepCopy()
+	op, err = r.createOrUpdate(ctx, app, binding, func() error {
+		for k, v := range wantBinding.Labels {
+			metav1.SetMetaDataLabel(&binding.ObjectMeta, k, v)
+		}
+		// The roleRef is immutable and always references the Role of the App
+		binding.RoleRef = wantBinding.RoleRef
+		binding.Subjects = wantBinding.Subjects
+		return ctrl.SetControllerReference(app, binding, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("RoleBinding reconciled", "operation", op, "name", binding.Name)
+	return nil
+}
+
+// desiredServiceAccount returns the ServiceAccount of the App, or nil when
+// spec.serviceAccount is not set
+func desiredServiceAccount(app *appv1.App) *corev1.ServiceAccount {
+	spec := app.Spec.ServiceAccount
+	if spec == nil {
+		return nil
+	}
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        app.ServiceAccountName(),
+			Namespace:   app.Namespace,
+			Labels:      app.SelectorLabels(),
+			Annotations: spec.Annotations,
+		},
+	}
+}
+
+// desiredRole returns the Role granting spec.serviceAccount.rules, or nil
+// when there are no rules
+func desiredRole(app *appv1.App) *rbacv1.Role {
+	spec := app.Spec.ServiceAccount
+	if spec == nil || len(spec.Rules) == 0 {
+		return nil
+	}
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.ServiceAccountName(),
+			Namespace: app.Namespace,
+			Labels:    app.SelectorLabels(),
+		},
+		Rules: spec.Rules,
+	}
+}
+
+// desiredRoleBinding returns the RoleBinding of the Role to the ServiceAccount,
+// or nil when there is no Role
+func desiredRoleBinding(app *appv1.App) *rbacv1.RoleBinding {
+	if desiredRole(app) == nil {
+		return nil
+	}
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.ServiceAccountName(),
+			Namespace: app.Namespace,
+			Labels:    app.SelectorLabels(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     app.ServiceAccountName(),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      app.ServiceAccountName(),
+			Namespace: app.Namespace,
+		}},
+	}
+}
+
+// podServiceAccountName returns the ServiceAccount of the pods, empty for the
+// default ServiceAccount of the namespace
+func podServiceAccountName(app *appv1.App) string {
+	if app.Spec.ServiceAccount == nil {
+		return ""
+	}
+	return app.ServiceAccountName()
+}
+
+// podAutomountToken returns whether the API token is mounted into the pods,
+// nil to leave the decision to the ServiceAccount
+func podAutomountToken(app *appv1.App) *bool {
+	if app.Spec.ServiceAccount == nil {
+		return nil
+	}
+	return app.Spec.ServiceAccount.AutomountToken
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestReconcileServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	rules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list"}}}
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.ServiceAccount = &appv1.ServiceAccountSpec{
+			Annotations:    map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/web"},
+			Rules:          rules,
+			AutomountToken: ptr.To(true),
+		}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{Client: c, Scheme: scheme}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+	key := client.ObjectKey{Namespace: "default", Name: "web-sa"}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := c.Get(ctx, key, sa); err != nil {
+		t.Fatal(err)
+	}
+	if got := sa.Annotations["eks.amazonaws.com/role-arn"]; got != "arn:aws:iam::123456789012:role/web" {
+		t.Errorf("ServiceAccount annotation = %q", got)
+	}
+	role := &rbacv1.Role{}
+	if err := c.Get(ctx, key, role); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(rules, role.Rules); diff != "" {
+		t.Errorf("Role rules mismatch (-want +got):\n%s", diff)
+	}
+	binding := &rbacv1.RoleBinding{}
+	if err := c.Get(ctx, key, binding); err != nil {
+		t.Fatal(err)
+	}
+	wantSubjects := []rbacv1.Subject{{Kind: "ServiceAccount", Name: "web-sa", Namespace: "default"}}
+	if binding.RoleRef.Name != "web-sa" || !cmp.Equal(wantSubjects, binding.Subjects) {
+		t.Errorf("RoleBinding = %+v %+v, want web-sa bound to web-sa", binding.RoleRef, binding.Subjects)
+	}
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	if pod := dep.Spec.Template.Spec; pod.ServiceAccountName != "web-sa" || !ptr.Deref(pod.AutomountServiceAccountToken, false) {
+		t.Errorf("pod ServiceAccount = %q, automount %v", pod.ServiceAccountName, pod.AutomountServiceAccountToken)
+	}
+
+	// Without rules, the Role and RoleBinding go away
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.ServiceAccount.Rules = nil
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range []client.Object{&rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+		if err := c.Get(ctx, key, obj); !apierrors.IsNotFound(err) {
+			t.Errorf("Get(%T) = %v, want NotFound", obj, err)
+		}
+	}
+	if err := c.Get(ctx, key, sa); err != nil {
+		t.Errorf("Get(ServiceAccount) = %v", err)
+	}
+
+	// Back to the default ServiceAccount of the namespace
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	app.Spec.ServiceAccount = nil
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, key, sa); !apierrors.IsNotFound(err) {
+		t.Errorf("Get(ServiceAccount) = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatal(err)
+	}
+	if pod := dep.Spec.Template.Spec; pod.ServiceAccountName != "" || pod.DeprecatedServiceAccount != "" || pod.AutomountServiceAccountToken != nil {
+		t.Errorf("pod ServiceAccount = %q, automount %v, want the default", pod.ServiceAccountName, pod.AutomountServiceAccountToken)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"hash/fnv"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Shard selects the subset of Apps reconciled by one operator replica.
+// Apps are spread over Count shards by hashing the value of Label, or
+// their namespace/name when the label is not set. The zero value owns every App.
+type Shard struct {
+	// Count is the total number of shards
+	Count int
+	// ID is the shard of this replica, from 0 to Count-1
+	ID int
+	// Label is the key of the label used as shard key
+	Label string
+}
+
+// Owns reports whether the object belongs to this shard
+func (s Shard) Owns(obj client.Object) bool {
+	if s.Count <= 1 {
+		return true
+	}
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	if v, ok := obj.GetLabels()[s.Label]; ok && s.Label != "" {
+		key = v
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32()%uint32(s.Count)) == s.ID
+}
+
+// NewRateLimiter returns the rate limiter of workqueue.DefaultControllerRateLimiter
+// with tunable settings: a per-App exponential backoff between baseDelay and
+// maxDelay on failures, capped by an overall token bucket of qps and burst.
+func NewRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
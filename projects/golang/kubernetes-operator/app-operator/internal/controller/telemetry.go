@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// instrumentationAnnotationPrefix prefixes the pod annotations read by the
+// OpenTelemetry Operator injection webhook
+const instrumentationAnnotationPrefix = "instrumentation.opentelemetry.io/"
+
+// telemetryAnnotations returns the pod annotations requesting the injection
+// of the auto-instrumentation matching the App language
+func telemetryAnnotations(app *appv1.App) map[string]string {
+	spec := app.Spec.Telemetry
+	if spec == nil {
+		return nil
+	}
+	instrumentation := spec.Instrumentation
+	if instrumentation == "" {
+		instrumentation = "true"
+	}
+	annotations := map[string]string{
+		instrumentationAnnotationPrefix + "inject-" + spec.Language: instrumentation,
+	}
+	if spec.Language == "go" && spec.ExecutablePath != "" {
+		annotations[instrumentationAnnotationPrefix+"otel-go-auto-target-exe"] = spec.ExecutablePath
+	}
+	return annotations
+}
+
+// containerEnv returns the environment of the app container: spec.env plus
+// the OTEL_* variables derived from spec.telemetry and the OLLAMA_* variables
+// of spec.inference. Variables set in spec.env win.
+func containerEnv(app *appv1.App) []corev1.EnvVar {
+	derived := append(telemetryEnv(app), inferenceEnv(app)...)
+	if len(derived) == 0 {
+		return app.Spec.Env
+	}
+
+	env := append([]corev1.EnvVar{}, app.Spec.Env...)
+	for _, e := range derived {
+		if !hasEnv(app.Spec.Env, e.Name) {
+			env = append(env, e)
+		}
+	}
+	return env
+}
+
+// telemetryEnv returns the OTEL_* variables derived from spec.telemetry
+func telemetryEnv(app *appv1.App) []corev1.EnvVar {
+	spec := app.Spec.Telemetry
+	if spec == nil {
+		return nil
+	}
+
+	otelEnv := []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: app.Name}}
+	if spec.Endpoint != "" {
+		otelEnv = append(otelEnv, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: spec.Endpoint})
+	}
+	if spec.SamplingRatio != "" {
+		otelEnv = append(otelEnv,
+			corev1.EnvVar{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+			corev1.EnvVar{Name: "OTEL_TRACES_SAMPLER_ARG", Value: spec.SamplingRatio},
+		)
+	}
+	return otelEnv
+}
+
+func hasEnv(env []corev1.EnvVar, name string) bool {
+	for _, e := range env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isInstrumentationAnnotation reports whether the pod annotation is managed
+// through spec.telemetry
+func isInstrumentationAnnotation(key string) bool {
+	return strings.HasPrefix(key, instrumentationAnnotationPrefix)
+}
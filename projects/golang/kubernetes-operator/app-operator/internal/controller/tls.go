@@ -0,0 +1,178 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
+// reconcileTLS applies the cert-manager Certificate requested by spec.tls, or
+// deletes it once spec.tls is removed, and reports its expiry in the status
+func (r *AppReconciler) reconcileTLS(ctx context.Context, app *appv1.App) error {
+	app.Status.TLS = nil
+	if !r.APIs.CertManager {
+		if app.Spec.TLS == nil {
+			meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionCertificateReady)
+			return nil
+		}
+		meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+			Type:               appv1.ConditionCertificateReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "CertManagerNotInstalled",
+			Message:            "The cert-manager.io/v1 API is not served by the cluster, the pods wait for Secret " + app.CertificateName(),
+			ObservedGeneration: app.Generation,
+		})
+		return nil
+	}
+
+	desired := desiredCertificate(app)
+	if desired == nil {
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionCertificateReady)
+		return r.deleteOwned(ctx, app, newUnstructured(certificateGVK, app.Namespace, app.CertificateName()))
+	}
+	cert, err := r.applyUnstructured(ctx, app, desired)
+	if err != nil {
+		return err
+	}
+	app.Status.TLS = certificateStatus(cert)
+	meta.SetStatusCondition(&app.Status.Conditions, certificateCondition(app, cert))
+	return nil
+}
+
+// desiredCertificate returns the Certificate of spec.tls, or nil when spec.tls is not set
+func desiredCertificate(app *appv1.App) *unstructured.Unstructured {
+	spec := app.Spec.TLS
+	if spec == nil {
+		return nil
+	}
+
+	kind, group := spec.IssuerRef.Kind, spec.IssuerRef.Group
+	if kind == "" {
+		kind = "Issuer"
+	}
+	if group == "" {
+		group = certificateGVK.Group
+	}
+	dnsNames := []interface{}{}
+	for _, name := range tlsDNSNames(app) {
+		dnsNames = append(dnsNames, name)
+	}
+
+	cert := newUnstructured(certificateGVK, app.Namespace, app.CertificateName())
+	cert.SetLabels(app.SelectorLabels())
+	cert.Object["spec"] = map[string]interface{}{
+		"secretName": app.CertificateName(),
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]interface{}{
+			"name":  spec.IssuerRef.Name,
+			"kind":  kind,
+			"group": group,
+		},
+	}
+	return cert
+}
+
+// certificateStatus returns the expiry of the certificate reported by the Certificate
+func certificateStatus(cert *unstructured.Unstructured) *appv1.TLSStatus {
+	secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	return &appv1.TLSStatus{
+		SecretName:  secretName,
+		NotAfter:    nestedTime(cert, "status", "notAfter"),
+		RenewalTime: nestedTime(cert, "status", "renewalTime"),
+	}
+}
+
+// certificateCondition mirrors the Ready condition of the Certificate in the
+// CertificateReady condition of the App
+func certificateCondition(app *appv1.App, cert *unstructured.Unstructured) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               appv1.ConditionCertificateReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Issuing",
+		Message:            "Certificate " + cert.GetName() + " is not issued yet",
+		ObservedGeneration: app.Generation,
+	}
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	for _, c := range conditions {
+		c, ok := c.(map[string]interface{})
+		if !ok || c["type"] != "Ready" {
+			continue
+		}
+		if message, _ := c["message"].(string); message != "" {
+			cond.Message = message
+		}
+		if c["status"] == string(metav1.ConditionTrue) {
+			cond.Status, cond.Reason = metav1.ConditionTrue, "Issued"
+			if notAfter := nestedTime(cert, "status", "notAfter"); notAfter != nil {
+				cond.Message = "Certificate " + cert.GetName() + " is valid until " + notAfter.UTC().Format(time.RFC3339)
+			}
+		}
+	}
+	return cond
+}
+
+// nestedTime returns the RFC 3339 time at the path of the object, or nil
+// when it is not set or malformed
+func nestedTime(obj *unstructured.Unstructured, fields ...string) *metav1.Time {
+	s, _, _ := unstructured.NestedString(obj.Object, fields...)
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: t}
+}
+
+// tlsDNSNames returns the DNS names of the certificate, defaulted to the host of the Ingress
+func tlsDNSNames(app *appv1.App) []string {
+	if names := app.Spec.TLS.DNSNames; len(names) > 0 {
+		return names
+	}
+	if app.Spec.Ingress != nil {
+		return []string{app.Spec.Ingress.Host}
+	}
+	return nil
+}
+
+// tlsVolume returns the pod volume of the Secret written by cert-manager.
+// The pods wait for the Secret until the certificate is issued.
+func tlsVolume(app *appv1.App) corev1.Volume {
+	return corev1.Volume{
+		Name: appv1.TLSVolumeName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: app.CertificateName(),
+		}},
+	}
+}
+
+// tlsVolumeMount returns the mount of the certificate in the app container
+func tlsVolumeMount(app *appv1.App) corev1.VolumeMount {
+	path := app.Spec.TLS.MountPath
+	if path == "" {
+		path = "/etc/tls"
+	}
+	return corev1.VolumeMount{Name: appv1.TLSVolumeName, MountPath: path, ReadOnly: true}
+}
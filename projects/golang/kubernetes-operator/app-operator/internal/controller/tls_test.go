@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestReconcileTLS(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	scheme.AddKnownTypeWithName(certificateGVK, &unstructured.Unstructured{})
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Ingress = &appv1.IngressSpec{Host: "web.example.com"}
+		a.Spec.TLS = &appv1.TLSSpec{IssuerRef: appv1.IssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"}}
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(app).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &AppReconciler{Client: c, Scheme: scheme, APIs: AvailableAPIs{CertManager: true}}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionCertificateReady, "Issuing")
+	cert := newUnstructured(certificateGVK, "default", "web-tls")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cert), cert); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"secretName": "web-tls",
+		"dnsNames":   []interface{}{"web.example.com"},
+		"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"},
+	}
+	if diff := cmp.Diff(want, cert.Object["spec"]); diff != "" {
+		t.Errorf("Certificate spec mismatch (-want +got):\n%s", diff)
+	}
+
+	ing := &networkingv1.Ingress{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-ingress"}, ing); err != nil {
+		t.Fatal(err)
+	}
+	wantTLS := []networkingv1.IngressTLS{{Hosts: []string{"web.example.com"}, SecretName: "web-tls"}}
+	if diff := cmp.Diff(wantTLS, ing.Spec.TLS); diff != "" {
+		t.Errorf("Ingress TLS mismatch (-want +got):\n%s", diff)
+	}
+
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	pod := dep.Spec.Template.Spec
+	if len(pod.Volumes) != 1 || pod.Volumes[0].Secret == nil || pod.Volumes[0].Secret.SecretName != "web-tls" {
+		t.Errorf("volumes = %+v, want the Secret web-tls", pod.Volumes)
+	}
+	if mounts := pod.Containers[0].VolumeMounts; len(mounts) != 1 || mounts[0].MountPath != "/etc/tls" || !mounts[0].ReadOnly {
+		t.Errorf("volume mounts = %+v, want /etc/tls read-only", mounts)
+	}
+
+	// cert-manager issues the certificate
+	notAfter := time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC)
+	cert.Object["status"] = map[string]interface{}{
+		"notAfter":    notAfter.Format(time.RFC3339),
+		"renewalTime": notAfter.Add(-30 * 24 * time.Hour).Format(time.RFC3339),
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "message": "Certificate is up to date and has not expired"},
+		},
+	}
+	if err := c.Update(ctx, cert); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertCondition(t, c, app, appv1.ConditionCertificateReady, "Issued")
+	if err := c.Get(ctx, req.NamespacedName, app); err != nil {
+		t.Fatal(err)
+	}
+	if status := app.Status.TLS; status == nil || status.SecretName != "web-tls" || !status.NotAfter.Time.Equal(notAfter) {
+		t.Errorf("status.tls = %+v, want web-tls expiring at %s", status, notAfter)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/usage"
+)
+
+const (
+	// overProvisionedRatio flags requests more than twice the p95 usage
+	overProvisionedRatio = 0.5
+	// recommendationHeadroom is added on top of the p95 usage in recommendations
+	recommendationHeadroom = 1.2
+	// minRecommendationSamples avoids recommendations based on a few samples
+	minRecommendationSamples = 10
+)
+
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+
+// reconcileUsage samples the usage of the app container when the last sample
+// is older than UsageSampleInterval, and reports the usage over the window
+// in the status with the RightSizing condition
+func (r *AppReconciler) reconcileUsage(ctx context.Context, app *appv1.App) {
+	if r.Usage == nil || r.UsageRecorder == nil {
+		app.Status.Usage = nil
+		meta.RemoveStatusCondition(&app.Status.Conditions, appv1.ConditionRightSizing)
+		return
+	}
+
+	key := client.ObjectKeyFromObject(app)
+	now := time.Now()
+	if now.Sub(r.UsageRecorder.Last(key)) >= r.UsageSampleInterval {
+		if err := r.sampleUsage(ctx, app, now); err != nil {
+			log.FromContext(ctx).Info("Failed to sample usage", "error", err.Error())
+			meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
+				Type:               appv1.ConditionRightSizing,
+				Status:             metav1.ConditionUnknown,
+				Reason:             "MetricsUnavailable",
+				Message:            err.Error(),
+				ObservedGeneration: app.Generation,
+			})
+			return
+		}
+	}
+
+	app.Status.Usage = usageStatus(r.UsageRecorder.Samples(key, now), containerResources(app).Requests)
+	setRightSizingCondition(app)
+}
+
+// sampleUsage records the current usage of the app container of every pod
+func (r *AppReconciler) sampleUsage(ctx context.Context, app *appv1.App, now time.Time) error {
+	usages, err := r.Usage.ContainerUsage(ctx, app.Namespace, labels.SelectorFromSet(app.SelectorLabels()))
+	if err != nil {
+		return err
+	}
+	sample := usage.Sample{Time: now}
+	for _, u := range usages {
+		if u.Container == appv1.AppContainerName {
+			sample.CPU = append(sample.CPU, u.CPU)
+			sample.Memory = append(sample.Memory, u.Memory)
+		}
+	}
+	// Nothing to record while no pod is running
+	if len(sample.CPU) > 0 {
+		r.UsageRecorder.Add(client.ObjectKeyFromObject(app), sample)
+	}
+	return nil
+}
+
+// usageStatus returns the usage percentiles of the samples, nil without samples
+func usageStatus(samples []usage.Sample, requests corev1.ResourceList) *appv1.ResourceUsage {
+	var cpu, memory []float64
+	for _, s := range samples {
+		cpu = append(cpu, s.CPU...)
+		memory = append(memory, s.Memory...)
+	}
+	if len(cpu) == 0 {
+		return nil
+	}
+	return &appv1.ResourceUsage{
+		Samples: int32(len(samples)),
+		CPU:     resourceStats(corev1.ResourceCPU, cpu, requests, len(samples)),
+		Memory:  resourceStats(corev1.ResourceMemory, memory, requests, len(samples)),
+	}
+}
+
+// resourceStats compares the usage of a resource, in millicores for CPU and
+// bytes for memory, to its request
+func resourceStats(name corev1.ResourceName, values []float64, requests corev1.ResourceList, samples int) appv1.ResourceStats {
+	p95 := usage.Percentile(values, 95)
+	stats := appv1.ResourceStats{
+		P50: usageQuantity(name, usage.Percentile(values, 50)),
+		P95: usageQuantity(name, p95),
+	}
+	request, ok := requests[name]
+	if !ok || request.IsZero() {
+		return stats
+	}
+	stats.Request = &request
+
+	ratio := p95 / usageAmount(name, request)
+	stats.RequestRatio = strconv.FormatFloat(ratio, 'f', 2, 64)
+	if samples >= minRecommendationSamples && ratio < overProvisionedRatio {
+		recommendation := usageQuantity(name, p95*recommendationHeadroom)
+		stats.Recommendation = &recommendation
+	}
+	return stats
+}
+
+// usageQuantity converts millicores or bytes to a quantity, memory being
+// rounded up to the mebibyte
+func usageQuantity(name corev1.ResourceName, v float64) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(int64(math.Ceil(v)), resource.DecimalSI)
+	}
+	const mebibyte = 1 << 20
+	return *resource.NewQuantity(int64(math.Ceil(v/mebibyte))*mebibyte, resource.BinarySI)
+}
+
+// usageAmount converts a quantity to millicores or bytes
+func usageAmount(name corev1.ResourceName, q resource.Quantity) float64 {
+	if name == corev1.ResourceCPU {
+		return float64(q.MilliValue())
+	}
+	return float64(q.Value())
+}
+
+// setRightSizingCondition reports the recommendations of the usage status
+func setRightSizingCondition(app *appv1.App) {
+	cond := metav1.Condition{
+		Type:               appv1.ConditionRightSizing,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: app.Generation,
+	}
+	u := app.Status.Usage
+	switch {
+	case u == nil:
+		cond.Reason, cond.Message = "NoSamples", "No usage sampled yet"
+	case u.CPU.Request == nil && u.Memory.Request == nil:
+		cond.Reason, cond.Message = "NoRequests", "spec.resources.requests is not set"
+	case u.Samples < minRecommendationSamples:
+		cond.Reason = "InsufficientSamples"
+		cond.Message = fmt.Sprintf("%d/%d samples", u.Samples, minRecommendationSamples)
+	default:
+		var recommendations []string
+		for _, r := range []struct {
+			name  corev1.ResourceName
+			stats appv1.ResourceStats
+		}{{corev1.ResourceCPU, u.CPU}, {corev1.ResourceMemory, u.Memory}} {
+			if r.stats.Recommendation != nil {
+				recommendations = append(recommendations, fmt.Sprintf("%s request %s for a p95 usage of %s, recommended %s",
+					r.name, r.stats.Request, &r.stats.P95, r.stats.Recommendation))
+			}
+		}
+		cond.Status, cond.Reason, cond.Message = metav1.ConditionFalse, "RightSized", "Requests match the usage"
+		if len(recommendations) > 0 {
+			cond.Status, cond.Reason, cond.Message = metav1.ConditionTrue, "OverProvisioned", strings.Join(recommendations, "; ")
+		}
+	}
+	meta.SetStatusCondition(&app.Status.Conditions, cond)
+}
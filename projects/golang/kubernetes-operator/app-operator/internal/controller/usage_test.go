@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/usage"
+)
+
+// fakeUsage reports the same usage for every pod
+type fakeUsage []usage.ContainerUsage
+
+func (f fakeUsage) ContainerUsage(context.Context, string, labels.Selector) ([]usage.ContainerUsage, error) {
+	return f, nil
+}
+
+func TestReconcileUsage(t *testing.T) {
+	ctx := context.Background()
+	scheme := newTestScheme(t)
+	app := newTestApp(func(a *appv1.App) {
+		a.Spec.Resources = &appv1.ResourcesSpec{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		}}
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(app).WithStatusSubresource(&appv1.App{}).Build()
+	r := &AppReconciler{
+		Client: c,
+		Scheme: scheme,
+		Usage: fakeUsage{
+			{Pod: "web-1", Container: "app", CPU: 100, Memory: 200 << 20},
+			{Pod: "web-1", Container: "proxy", CPU: 900, Memory: 10 << 20},
+		},
+		UsageRecorder:       usage.NewRecorder(time.Hour),
+		UsageSampleInterval: time.Minute,
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}
+
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RequeueAfter != time.Minute {
+		t.Errorf("RequeueAfter = %s, want the sample interval", res.RequeueAfter)
+	}
+	assertCondition(t, c, app, appv1.ConditionRightSizing, "InsufficientSamples")
+
+	// A window of samples: the CPU request is 10 times the usage
+	r.UsageRecorder = usage.NewRecorder(time.Hour)
+	now := time.Now()
+	for i := minRecommendationSamples - 1; i >= 0; i-- {
+		cpu := float64(50 + i)
+		if i == 0 {
+			cpu = 100
+		}
+		r.UsageRecorder.Add(req.NamespacedName, usage.Sample{
+			Time:   now.Add(-time.Duration(i) * time.Minute),
+			CPU:    []float64{cpu},
+			Memory: []float64{200 << 20},
+		})
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	got := &appv1.App{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatal(err)
+	}
+	u := got.Status.Usage
+	if u == nil || u.Samples != minRecommendationSamples {
+		t.Fatalf("usage = %+v, want %d samples", u, minRecommendationSamples)
+	}
+	if u.CPU.P95.String() != "100m" || u.CPU.P50.String() != "55m" || u.CPU.RequestRatio != "0.10" {
+		t.Errorf("cpu = p50 %s, p95 %s, ratio %s", &u.CPU.P50, &u.CPU.P95, u.CPU.RequestRatio)
+	}
+	if u.CPU.Recommendation == nil || u.CPU.Recommendation.String() != "120m" {
+		t.Errorf("cpu recommendation = %v, want 120m", u.CPU.Recommendation)
+	}
+	if u.Memory.RequestRatio != "0.78" || u.Memory.Recommendation != nil {
+		t.Errorf("memory = ratio %s, recommendation %v", u.Memory.RequestRatio, u.Memory.Recommendation)
+	}
+	assertCondition(t, c, app, appv1.ConditionRightSizing, "OverProvisioned")
+}
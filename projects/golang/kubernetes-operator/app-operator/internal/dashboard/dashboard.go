@@ -0,0 +1,267 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboard serves a read-only summary of the Apps over HTTP, as JSON
+// and as an HTML page.
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// ReconcileTimes keeps the time each App was last reconciled by this replica.
+// The times live in memory rather than in the status, which would trigger
+// another reconciliation on every write.
+type ReconcileTimes struct {
+	mu    sync.RWMutex
+	times map[types.NamespacedName]time.Time
+}
+
+// NewReconcileTimes returns an empty ReconcileTimes
+func NewReconcileTimes() *ReconcileTimes {
+	return &ReconcileTimes{times: map[types.NamespacedName]time.Time{}}
+}
+
+// Record sets the last reconcile time of an App
+func (r *ReconcileTimes) Record(key types.NamespacedName, t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.times[key] = t
+}
+
+// Get returns the last reconcile time of an App, zero when unknown
+func (r *ReconcileTimes) Get(key types.NamespacedName) time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.times[key]
+}
+
+// Forget drops the time of a deleted App
+func (r *ReconcileTimes) Forget(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.times, key)
+}
+
+// Condition is the summary of a condition of an App
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// AppSummary is the line of an App in the dashboard
+type AppSummary struct {
+	Namespace       string      `json:"namespace"`
+	Name            string      `json:"name"`
+	Phase           string      `json:"phase"`
+	Image           string      `json:"image"`
+	ReadyReplicas   int32       `json:"readyReplicas"`
+	DesiredReplicas int32       `json:"desiredReplicas"`
+	Conditions      []Condition `json:"conditions,omitempty"`
+	// LastReconcileTime is unset when the App was not reconciled by this replica,
+	// e.g. when it belongs to the shard of another replica
+	LastReconcileTime *time.Time `json:"lastReconcileTime,omitempty"`
+}
+
+// Server serves the dashboard. It runs on every replica of the operator.
+type Server struct {
+	// Addr is the address the dashboard binds to
+	Addr string
+	// Reader lists the Apps, usually the cached client of the manager
+	Reader client.Reader
+	// ReconcileTimes are the last reconcile times recorded by the App controller
+	ReconcileTimes *ReconcileTimes
+}
+
+// Start implements manager.Runnable, serving until ctx is done
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		logf.FromContext(ctx).Info("Serving dashboard", "addr", s.Addr)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: every replica serves the dashboard
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the handler of the dashboard: the HTML page on /, the JSON
+// summary on /apps. Both accept a namespace query parameter.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apps", func(w http.ResponseWriter, r *http.Request) {
+		apps, ok := s.summaries(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(apps)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		apps, ok := s.summaries(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, apps); err != nil {
+			logf.FromContext(r.Context()).Error(err, "Failed to render dashboard")
+		}
+	})
+	return allowGet(mux)
+}
+
+// summaries lists the Apps of the namespace query parameter, all of them when
+// empty, ordered by namespace and name
+func (s *Server) summaries(w http.ResponseWriter, r *http.Request) ([]AppSummary, bool) {
+	apps := &appv1.AppList{}
+	var opts []client.ListOption
+	if ns := r.URL.Query().Get("namespace"); ns != "" {
+		opts = append(opts, client.InNamespace(ns))
+	}
+	if err := s.Reader.List(r.Context(), apps, opts...); err != nil {
+		logf.FromContext(r.Context()).Error(err, "Failed to list Apps")
+		http.Error(w, "failed to list Apps", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	summaries := make([]AppSummary, 0, len(apps.Items))
+	for i := range apps.Items {
+		summaries = append(summaries, s.summary(&apps.Items[i]))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries, true
+}
+
+func (s *Server) summary(app *appv1.App) AppSummary {
+	sum := AppSummary{
+		Namespace:       app.Namespace,
+		Name:            app.Name,
+		Phase:           app.Status.Phase,
+		Image:           app.Spec.Image,
+		ReadyReplicas:   app.Status.ReadyReplicas,
+		DesiredReplicas: 1,
+	}
+	if sum.Phase == "" {
+		sum.Phase = "Unknown"
+	}
+	if app.Spec.Replicas != nil {
+		sum.DesiredReplicas = *app.Spec.Replicas
+	}
+	for _, c := range app.Status.Conditions {
+		sum.Conditions = append(sum.Conditions, Condition{
+			Type: c.Type, Status: string(c.Status), Reason: c.Reason, Message: c.Message,
+		})
+	}
+	if s.ReconcileTimes != nil {
+		if t := s.ReconcileTimes.Get(client.ObjectKeyFromObject(app)); !t.IsZero() {
+			sum.LastReconcileTime = &t
+		}
+	}
+	return sum
+}
+
+// allowGet rejects the methods other than GET and HEAD: the dashboard is read-only
+func allowGet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+var page = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"since": func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return time.Since(*t).Round(time.Second).String() + " ago"
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Apps</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+.False { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Apps</h1>
+<table>
+<tr><th>Namespace</th><th>Name</th><th>Phase</th><th>Ready</th><th>Image</th><th>Conditions</th><th>Last reconcile</th></tr>
+{{- range . }}
+<tr>
+<td>{{ .Namespace }}</td><td>{{ .Name }}</td><td>{{ .Phase }}</td>
+<td>{{ .ReadyReplicas }}/{{ .DesiredReplicas }}</td><td>{{ .Image }}</td>
+<td>{{ range .Conditions }}<div class="{{ .Status }}" title="{{ .Message }}">{{ .Type }}={{ .Status }}{{ with .Reason }} ({{ . }}){{ end }}</div>{{ end }}</td>
+<td>{{ since .LastReconcileTime }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,122 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := appv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	web := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec:       appv1.AppSpec{Image: "nginx:1.27", Replicas: ptr.To[int32](3)},
+		Status: appv1.AppStatus{
+			Phase:         "Running",
+			ReadyReplicas: 2,
+			Conditions: []metav1.Condition{{
+				Type: appv1.ConditionSpecValid, Status: metav1.ConditionTrue, Reason: "Valid",
+			}},
+		},
+	}
+	api := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "billing"},
+		Spec:       appv1.AppSpec{Image: "api:<b>1</b>"},
+	}
+	times := NewReconcileTimes()
+	times.Record(types.NamespacedName{Namespace: "shop", Name: "web"}, time.Now().Add(-time.Minute))
+	return &Server{
+		Reader:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(web, api).Build(),
+		ReconcileTimes: times,
+	}
+}
+
+func TestDashboardJSON(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apps", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("GET /apps = %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var apps []AppSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &apps); err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 2 || apps[0].Name != "api" || apps[1].Name != "web" {
+		t.Fatalf("apps = %+v, want api then web", apps)
+	}
+	if api := apps[0]; api.Phase != "Unknown" || api.DesiredReplicas != 1 || api.LastReconcileTime != nil {
+		t.Errorf("api = %+v", api)
+	}
+	web := apps[1]
+	if web.Phase != "Running" || web.ReadyReplicas != 2 || web.DesiredReplicas != 3 || web.Image != "nginx:1.27" {
+		t.Errorf("web = %+v", web)
+	}
+	if len(web.Conditions) != 1 || web.Conditions[0].Type != appv1.ConditionSpecValid || web.LastReconcileTime == nil {
+		t.Errorf("web = %+v", web)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apps?namespace=shop", nil))
+	apps = nil
+	if err := json.Unmarshal(rec.Body.Bytes(), &apps); err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].Namespace != "shop" {
+		t.Errorf("apps of shop = %+v", apps)
+	}
+}
+
+func TestDashboardHTML(t *testing.T) {
+	h := newTestServer(t).Handler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET / = %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{"<td>web</td>", "<td>2/3</td>", "SpecValid=True (Valid)", "1m0s ago", "api:&lt;b&gt;1&lt;/b&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/apps", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /apps = %d, want 405", rec.Code)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package healthcheck performs the HTTP, TCP and gRPC checks of
+// spec.healthChecks from the operator.
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// Prober performs a check against an address (host:port). The deadline of
+// the context bounds the check.
+type Prober interface {
+	Probe(ctx context.Context, address string, check appv1.HealthCheck) error
+}
+
+// NetProber probes over the network
+type NetProber struct {
+	client *http.Client
+}
+
+// NewNetProber returns a Prober opening a new connection for every check,
+// so that checks are not served by a connection kept to another pod
+func NewNetProber() *NetProber {
+	return &NetProber{client: &http.Client{
+		Transport: &http.Transport{DisableKeepAlives: true},
+		// Redirects are a valid answer, as for kubelet probes
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}}
+}
+
+// Probe implements Prober
+func (p *NetProber) Probe(ctx context.Context, address string, check appv1.HealthCheck) error {
+	switch {
+	case check.HTTP != nil:
+		return p.probeHTTP(ctx, address, check.HTTP)
+	case check.TCP != nil:
+		return probeTCP(ctx, address)
+	case check.GRPC != nil:
+		return probeGRPC(ctx, address, check.GRPC)
+	}
+	return errors.New("no http, tcp or grpc check")
+}
+
+func (p *NetProber) probeHTTP(ctx context.Context, address string, check *appv1.HTTPHealthCheck) error {
+	path := check.Path
+	if path == "" {
+		path = "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "app-operator-healthcheck")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return nil
+}
+
+func probeTCP(ctx context.Context, address string) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func probeGRPC(ctx context.Context, address string, check *appv1.GRPCHealthCheck) error {
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: check.Service})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("gRPC health: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func probe(t *testing.T, address string, check appv1.HealthCheck) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return NewNetProber().Probe(ctx, address, check)
+}
+
+func TestProbeHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusNoContent)
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	address := strings.TrimPrefix(srv.URL, "http://")
+
+	for path, wantErr := range map[string]bool{"/healthz": false, "/moved": false, "/down": true} {
+		err := probe(t, address, appv1.HealthCheck{HTTP: &appv1.HTTPHealthCheck{Path: path}})
+		if (err != nil) != wantErr {
+			t.Errorf("Probe(%s) = %v, want error %v", path, err, wantErr)
+		}
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := l.Addr().String()
+	check := appv1.HealthCheck{TCP: &appv1.TCPHealthCheck{}}
+
+	if err := probe(t, address, check); err != nil {
+		t.Errorf("Probe() = %v on a listening port", err)
+	}
+	l.Close()
+	if err := probe(t, address, check); err == nil {
+		t.Error("Probe() succeeded on a closed port")
+	}
+}
+
+func TestProbeGRPC(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	status := health.NewServer()
+	status.SetServingStatus("inference", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(srv, status)
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Stop()
+
+	if err := probe(t, l.Addr().String(), appv1.HealthCheck{GRPC: &appv1.GRPCHealthCheck{}}); err != nil {
+		t.Errorf("Probe() = %v, want the server SERVING", err)
+	}
+	if err := probe(t, l.Addr().String(), appv1.HealthCheck{GRPC: &appv1.GRPCHealthCheck{Service: "inference"}}); err == nil {
+		t.Error("Probe() succeeded on a NOT_SERVING service")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kstatus generates the Argo CD health check of the resources of the
+// operator, which follow the kstatus conventions of sigs.k8s.io/cli-utils:
+// status.observedGeneration and the Ready, Reconciling and Stalled conditions.
+package kstatus
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// HealthScript is the Argo CD health check of the resources following the
+// kstatus conventions. It maps the statuses computed by kstatus to the Argo CD
+// health statuses: Failed is Degraded, InProgress is Progressing and Current is
+// Healthy. A True Paused condition makes the resource Suspended, and a Ready
+// condition that is not True keeps it Progressing for the kinds without a
+// Reconciling condition.
+const HealthScript = `local hs = {}
+if obj.status == nil then
+  hs.status = "Progressing"
+  hs.message = "Waiting for the status"
+  return hs
+end
+if obj.status.observedGeneration ~= nil and obj.status.observedGeneration ~= obj.metadata.generation then
+  hs.status = "Progressing"
+  hs.message = "Waiting for the controller to observe generation " .. tostring(obj.metadata.generation)
+  return hs
+end
+local conditions = {}
+if obj.status.conditions ~= nil then
+  for _, c in ipairs(obj.status.conditions) do
+    conditions[c.type] = c
+  end
+end
+local function isTrue(type)
+  return conditions[type] ~= nil and conditions[type].status == "True"
+end
+local function message(type)
+  if conditions[type] ~= nil and conditions[type].message ~= nil then
+    return conditions[type].message
+  end
+  return ""
+end
+if isTrue("` + appv1.ConditionPaused + `") then
+  hs.status = "Suspended"
+  hs.message = message("` + appv1.ConditionPaused + `")
+  return hs
+end
+if isTrue("` + appv1.ConditionStalled + `") then
+  hs.status = "Degraded"
+  hs.message = message("` + appv1.ConditionStalled + `")
+  return hs
+end
+if isTrue("` + appv1.ConditionReconciling + `") then
+  hs.status = "Progressing"
+  hs.message = message("` + appv1.ConditionReconciling + `")
+  return hs
+end
+if conditions["` + appv1.ConditionReady + `"] ~= nil and conditions["` + appv1.ConditionReady + `"].status ~= "True" then
+  hs.status = "Progressing"
+  hs.message = message("` + appv1.ConditionReady + `")
+  return hs
+end
+hs.status = "Healthy"
+hs.message = message("` + appv1.ConditionReady + `")
+return hs
+`
+
+// HealthScriptKey returns the key of the argocd-cm ConfigMap holding the
+// health check of a kind
+func HealthScriptKey(gk schema.GroupKind) string {
+	return "resource.customizations.health." + gk.Group + "_" + gk.Kind
+}
+
+// ArgoCDConfig returns the data of the argocd-cm ConfigMap configuring the
+// health check of the kinds
+func ArgoCDConfig(gks ...schema.GroupKind) map[string]string {
+	data := make(map[string]string, len(gks))
+	for _, gk := range gks {
+		data[HealthScriptKey(gk)] = HealthScript
+	}
+	return data
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kstatus
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestArgoCDConfig(t *testing.T) {
+	data := ArgoCDConfig(schema.GroupKind{Group: "apps.test.local", Kind: "App"})
+	if len(data) != 1 || data["resource.customizations.health.apps.test.local_App"] != HealthScript {
+		t.Errorf("ArgoCDConfig() keys = %v", data)
+	}
+}
+
+func TestHealthScriptOrder(t *testing.T) {
+	// The first true condition decides the health, as kstatus computes it
+	last := -1
+	for _, check := range []string{
+		`obj.status.observedGeneration ~= obj.metadata.generation`,
+		`isTrue("` + appv1.ConditionPaused + `")`,
+		`isTrue("` + appv1.ConditionStalled + `")`,
+		`isTrue("` + appv1.ConditionReconciling + `")`,
+		`conditions["` + appv1.ConditionReady + `"].status ~= "True"`,
+		`hs.status = "Healthy"`,
+	} {
+		i := strings.Index(HealthScript, check)
+		if i <= last {
+			t.Fatalf("HealthScript checks %s at %d, want after %d", check, i, last)
+		}
+		last = i
+	}
+}
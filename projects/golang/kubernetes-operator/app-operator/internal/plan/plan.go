@@ -0,0 +1,225 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan runs the reconciliation of the operator without making its
+// changes, to review the impact of an App before it is applied: against a
+// cluster, for the changes to the live objects, or offline, for the objects
+// owned by the App.
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+	"github.com/balleon/app-operator/internal/controller"
+	"github.com/balleon/app-operator/internal/secretstore"
+)
+
+// Planner runs the reconciliation of the operator against the cluster:
+// the live objects are read and the writes are sent as dry-run requests.
+type Planner struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// APIs lists the optional APIs served by the cluster
+	APIs controller.AvailableAPIs
+	// MonitorLabels are added to every ServiceMonitor and PodMonitor
+	MonitorLabels map[string]string
+	// SecretProviders read the secrets of spec.secretsFrom
+	SecretProviders map[appv1.SecretProvider]secretstore.Provider
+}
+
+// Plan returns the changes the reconciliation of app would make to its owned
+// objects, in the order they would be made. app is the App as it would be
+// applied, it does not need to exist yet.
+func (p *Planner) Plan(ctx context.Context, app *appv1.App) ([]audit.Record, error) {
+	admitted, err := p.admit(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &recordSink{}
+	c := &planClient{Client: client.NewDryRunClient(p.Client), app: admitted}
+	r := &controller.AppReconciler{
+		Client:          c,
+		Scheme:          p.Scheme,
+		APIs:            p.APIs,
+		MonitorLabels:   p.MonitorLabels,
+		SecretProviders: p.SecretProviders,
+		Audit:           &audit.Logger{Sink: sink, Scheme: p.Scheme, DryRun: true},
+		DryRun:          true,
+	}
+	if err := run(ctx, r, c); err != nil {
+		return nil, err
+	}
+	return sink.records, nil
+}
+
+// run reconciles the App of c, and fails when the App would be rejected
+// because of its spec or the policy
+func run(ctx context.Context, r *controller.AppReconciler, c *planClient) error {
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(c.app)}); err != nil {
+		return err
+	}
+	if cond := meta.FindStatusCondition(c.app.Status.Conditions, appv1.ConditionStalled); c.app.Status.Phase == "Failed" && cond != nil {
+		return fmt.Errorf("App %s would not be reconciled: %s", c.app.Name, cond.Message)
+	}
+	return nil
+}
+
+// admit returns app as the API server would store it, with the defaults of
+// the CRD, once accepted by the validating webhook
+func (p *Planner) admit(ctx context.Context, app *appv1.App) (*appv1.App, error) {
+	admitted := app.DeepCopy()
+	live := &appv1.App{}
+	err := p.Client.Get(ctx, client.ObjectKeyFromObject(app), live)
+	switch {
+	case apierrors.IsNotFound(err):
+		admitted.ResourceVersion = ""
+		err = p.Client.Create(ctx, admitted, client.DryRunAll)
+	case err == nil:
+		// The owned objects are matched to the live App by UID
+		admitted.UID = live.UID
+		admitted.ResourceVersion = live.ResourceVersion
+		err = p.Client.Update(ctx, admitted, client.DryRunAll)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("App %s would not be admitted: %w", app.Name, err)
+	}
+	return admitted, nil
+}
+
+// planClient is the client of the planned reconciliation. The planned App is
+// read from memory and its writes are kept there, the writes of the other
+// objects go to the wrapped dry-run client.
+type planClient struct {
+	client.Client
+	app *appv1.App
+}
+
+// isApp reports whether obj is the planned App
+func (c *planClient) isApp(obj client.Object) bool {
+	_, ok := obj.(*appv1.App)
+	return ok && obj.GetNamespace() == c.app.Namespace && obj.GetName() == c.app.Name
+}
+
+func (c *planClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if app, ok := obj.(*appv1.App); ok && key == client.ObjectKeyFromObject(c.app) {
+		c.app.DeepCopyInto(app)
+		return nil
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *planClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.isApp(obj) {
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *planClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.isApp(obj) {
+		c.app = obj.(*appv1.App).DeepCopy()
+		return nil
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *planClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.isApp(obj) {
+		c.app = obj.(*appv1.App).DeepCopy()
+		return nil
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *planClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.isApp(obj) {
+		return errors.New("the planned App cannot be deleted")
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *planClient) Status() client.SubResourceWriter {
+	return &planStatusWriter{SubResourceWriter: c.Client.Status(), c: c}
+}
+
+// planStatusWriter keeps the status of the planned App in memory
+type planStatusWriter struct {
+	client.SubResourceWriter
+	c *planClient
+}
+
+func (w *planStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if w.c.isApp(obj) {
+		w.c.app.Status = *obj.(*appv1.App).Status.DeepCopy()
+		return nil
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *planStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if w.c.isApp(obj) {
+		w.c.app.Status = *obj.(*appv1.App).Status.DeepCopy()
+		return nil
+	}
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// recordSink keeps the audit records of the planned changes
+type recordSink struct {
+	records []audit.Record
+}
+
+// Write implements audit.Sink
+func (s *recordSink) Write(_ context.Context, rec audit.Record) error {
+	s.records = append(s.records, rec)
+	return nil
+}
+
+// Decode reads the Apps of a YAML or JSON stream, e.g. a manifest of several
+// documents. Documents of other kinds are rejected.
+func Decode(r io.Reader) ([]*appv1.App, error) {
+	var apps []*appv1.App
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		app := &appv1.App{}
+		if err := decoder.Decode(app); errors.Is(err, io.EOF) {
+			return apps, nil
+		} else if err != nil {
+			return nil, err
+		}
+		// Empty documents
+		if app.APIVersion == "" && app.Kind == "" && app.Name == "" {
+			continue
+		}
+		if gvk := app.GroupVersionKind(); gvk != appv1.GroupVersion.WithKind("App") {
+			return nil, fmt.Errorf("document %s %q is not an App", gvk.Kind, app.Name)
+		}
+		apps = append(apps, app)
+	}
+}
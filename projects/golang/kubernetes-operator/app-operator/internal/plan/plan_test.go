@@ -0,0 +1,187 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/audit"
+	"github.com/balleon/app-operator/internal/controller"
+)
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	live := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid"},
+		Spec: appv1.AppSpec{
+			Image:    "nginx:1.27",
+			Replicas: ptr.To[int32](2),
+			Port:     8080,
+			Ingress:  &appv1.IngressSpec{Host: "web.example.com"},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(live).
+		WithStatusSubresource(&appv1.App{}).
+		Build()
+	r := &controller.AppReconciler{Client: c, Scheme: scheme}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(live)}); err != nil {
+		t.Fatal(err)
+	}
+	p := &Planner{Client: c, Scheme: scheme}
+
+	// A change to a live App
+	app := live.DeepCopy()
+	app.UID = ""
+	app.Spec.Replicas = ptr.To[int32](3)
+	app.Spec.Ingress = nil
+	records, err := p.Plan(ctx, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := map[string]audit.Record{}
+	for _, rec := range records {
+		if !rec.DryRun || rec.App.UID != "web-uid" {
+			t.Errorf("record %+v, want a dry run of the live App", rec)
+		}
+		changes[rec.Object.Kind+"/"+rec.Object.Name] = rec
+	}
+	if rec := changes["Deployment/web-app"]; rec.Operation != controllerutil.OperationResultUpdated ||
+		len(rec.Changes) != 1 || rec.Changes[0].Path != "spec.replicas" {
+		t.Errorf("Deployment record = %+v, want spec.replicas updated", rec)
+	}
+	if rec := changes["Ingress/web-ingress"]; rec.Operation != audit.OperationDeleted {
+		t.Errorf("Ingress record = %+v, want deleted", rec)
+	}
+	if _, ok := changes["Service/web-svc"]; ok {
+		t.Error("unchanged Service recorded")
+	}
+
+	// Nothing is written
+	dep := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-app"}, dep); err != nil {
+		t.Fatal(err)
+	}
+	if *dep.Spec.Replicas != 2 {
+		t.Errorf("Deployment replicas = %d, want 2", *dep.Spec.Replicas)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "web-ingress"}, &networkingv1.Ingress{}); err != nil {
+		t.Errorf("Get(Ingress) = %v, want the live Ingress", err)
+	}
+	got := &appv1.App{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(live), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Ingress == nil || *got.Spec.Replicas != 2 {
+		t.Errorf("live App spec = %+v, want it unchanged", got.Spec)
+	}
+
+	// A switch to a headless Service recreates the Service
+	app = live.DeepCopy()
+	app.UID = ""
+	app.Spec.Service = &appv1.ServiceSpec{Type: appv1.ServiceTypeHeadless}
+	if records, err = p.Plan(ctx, app); err != nil {
+		t.Fatal(err)
+	}
+	var service []string
+	for _, rec := range records {
+		if rec.Object.Kind == "Service" {
+			service = append(service, string(rec.Operation))
+			if rec.Operation == controllerutil.OperationResultCreated && rec.Changes == nil {
+				t.Errorf("Service record = %+v, want the created Service", rec)
+			}
+		}
+	}
+	if strings.Join(service, ",") != "deleted,created" {
+		t.Errorf("Service operations = %v, want deleted then created", service)
+	}
+
+	// A new App
+	app = &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec:       appv1.AppSpec{Image: "ghcr.io/acme/api:1.0", Port: 9090},
+	}
+	records, err = p.Plan(ctx, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var created []string
+	for _, rec := range records {
+		if rec.Operation == controllerutil.OperationResultCreated {
+			created = append(created, rec.Object.Kind+"/"+rec.Object.Name)
+		}
+	}
+	if strings.Join(created, ",") != "Deployment/api-app,Service/api-svc" {
+		t.Errorf("created = %v, want the Deployment and the Service", created)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(app), &appv1.App{}); err == nil {
+		t.Error("planned App created")
+	}
+
+	// An invalid App
+	app.Spec.TLS = &appv1.TLSSpec{IssuerRef: appv1.IssuerReference{Name: "letsencrypt"}}
+	if _, err := p.Plan(ctx, app); err == nil || !strings.Contains(err.Error(), "would not be reconciled") {
+		t.Errorf("Plan(invalid) = %v, want the validation error", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	apps, err := Decode(strings.NewReader(`---
+apiVersion: apps.test.local/v1
+kind: App
+metadata:
+  name: web
+spec:
+  image: nginx:1.27
+  port: 8080
+---
+---
+{"apiVersion": "apps.test.local/v1", "kind": "App", "metadata": {"name": "api"}, "spec": {"image": "api:1.0", "port": 9090}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 2 || apps[0].Name != "web" || apps[0].Spec.Port != 8080 || apps[1].Name != "api" {
+		t.Errorf("Decode = %+v, want web and api", apps)
+	}
+
+	if _, err := Decode(strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n")); err == nil {
+		t.Error("Decode(ConfigMap) succeeded")
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/config/crd"
+	"github.com/balleon/app-operator/internal/controller"
+)
+
+// errNoCluster is returned by the calls the reconciliation only makes against a cluster
+var errNoCluster = errors.New("not supported without a cluster")
+
+// Renderer runs the reconciliation of the operator without a cluster, as if
+// none of the owned objects existed yet
+type Renderer struct {
+	Scheme *runtime.Scheme
+	// APIs lists the optional APIs the objects are rendered for
+	APIs controller.AvailableAPIs
+	// MonitorLabels are added to every ServiceMonitor and PodMonitor
+	MonitorLabels map[string]string
+}
+
+// Render returns the objects owned by app, in the order the reconciliation
+// creates them. app is defaulted from the schema of the CRD first, as by the
+// API server. The fields only known to a cluster are left out: the status,
+// the owner references and the metadata set by the API server. The Secret of
+// spec.secretsFrom is not rendered, the secret stores are not read.
+func (r *Renderer) Render(ctx context.Context, app *appv1.App) ([]*unstructured.Unstructured, error) {
+	defaulted, err := applyDefaults(app)
+	if err != nil {
+		return nil, err
+	}
+	store := &memoryClient{scheme: r.Scheme}
+	c := &planClient{Client: store, app: defaulted}
+	rec := &controller.AppReconciler{
+		Client:        c,
+		Scheme:        r.Scheme,
+		APIs:          r.APIs,
+		MonitorLabels: r.MonitorLabels,
+	}
+	if err := run(ctx, rec, c); err != nil {
+		return nil, err
+	}
+
+	objs := make([]*unstructured.Unstructured, 0, len(store.objects))
+	for _, obj := range store.objects {
+		gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvk)
+		unstructured.RemoveNestedField(u.Object, "status")
+		for _, f := range []string{"ownerReferences", "resourceVersion", "uid", "creationTimestamp"} {
+			unstructured.RemoveNestedField(u.Object, "metadata", f)
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
+
+// appSchema returns the structural schema of the App CRD
+var appSchema = sync.OnceValues(func() (*structuralschema.Structural, error) {
+	def := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(crd.App, def); err != nil {
+		return nil, err
+	}
+	for _, v := range def.Spec.Versions {
+		if v.Name != appv1.GroupVersion.Version || v.Schema == nil {
+			continue
+		}
+		props := &apiextensions.JSONSchemaProps{}
+		if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(v.Schema.OpenAPIV3Schema, props, nil); err != nil {
+			return nil, err
+		}
+		return structuralschema.NewStructural(props)
+	}
+	return nil, fmt.Errorf("no schema of %s in the App CRD", appv1.GroupVersion)
+})
+
+// applyDefaults returns a copy of app with the defaults of the CRD, e.g. the
+// automountServiceAccountToken of an empty spec.serviceAccount
+func applyDefaults(app *appv1.App) (*appv1.App, error) {
+	s, err := appSchema()
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+	if err != nil {
+		return nil, err
+	}
+	structuraldefaulting.Default(content, s)
+	defaulted := &appv1.App{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, defaulted); err != nil {
+		return nil, err
+	}
+	return defaulted, nil
+}
+
+// memoryClient keeps the objects created by a reconciliation without a
+// cluster, in the order they are created. It implements the calls of the
+// App reconciliation without health checks nor usage report.
+type memoryClient struct {
+	client.Client
+	scheme  *runtime.Scheme
+	objects []client.Object
+}
+
+// index returns the index of the stored object of the kind and key of obj, or -1
+func (c *memoryClient) index(obj client.Object, key client.ObjectKey) (int, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return 0, err
+	}
+	for i, o := range c.objects {
+		if oGVK, _ := apiutil.GVKForObject(o, c.scheme); oGVK == gvk && client.ObjectKeyFromObject(o) == key {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// groupResource returns the resource of obj in the errors of the API server
+func (c *memoryClient) groupResource(obj client.Object) schema.GroupResource {
+	gvk, _ := apiutil.GVKForObject(obj, c.scheme)
+	return schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}
+}
+
+func (c *memoryClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	i, err := c.index(obj, key)
+	if err != nil {
+		return err
+	}
+	if i < 0 {
+		return apierrors.NewNotFound(c.groupResource(obj), key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(c.objects[i].DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *memoryClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	i, err := c.index(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return err
+	}
+	if i >= 0 {
+		return apierrors.NewAlreadyExists(c.groupResource(obj), obj.GetName())
+	}
+	c.objects = append(c.objects, obj.DeepCopyObject().(client.Object))
+	return nil
+}
+
+func (c *memoryClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	i, err := c.index(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return err
+	}
+	if i < 0 {
+		return apierrors.NewNotFound(c.groupResource(obj), obj.GetName())
+	}
+	c.objects[i] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *memoryClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	i, err := c.index(obj, client.ObjectKeyFromObject(obj))
+	if err != nil {
+		return err
+	}
+	if i < 0 {
+		return apierrors.NewNotFound(c.groupResource(obj), obj.GetName())
+	}
+	c.objects = append(c.objects[:i], c.objects[i+1:]...)
+	return nil
+}
+
+func (c *memoryClient) Patch(context.Context, client.Object, client.Patch, ...client.PatchOption) error {
+	return errNoCluster
+}
+
+func (c *memoryClient) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return errNoCluster
+}
+
+func (c *memoryClient) Status() client.SubResourceWriter {
+	return noClusterWriter{}
+}
+
+// noClusterWriter fails the writes of subresources, only the status of the
+// App is written by the reconciliation without a cluster
+type noClusterWriter struct{}
+
+func (noClusterWriter) Create(context.Context, client.Object, client.Object, ...client.SubResourceCreateOption) error {
+	return errNoCluster
+}
+
+func (noClusterWriter) Update(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+	return errNoCluster
+}
+
+func (noClusterWriter) Patch(context.Context, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+	return errNoCluster
+}
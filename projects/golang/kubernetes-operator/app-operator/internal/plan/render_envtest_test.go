@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/controller"
+)
+
+// TestRenderParity compares the rendered objects to the objects reconciled
+// against the API server of envtest, which applies the defaults of the CRD.
+func TestRenderParity(t *testing.T) {
+	assets := filepath.Join("..", "..", "bin", "k8s", fmt.Sprintf("1.30.0-%s-%s", goruntime.GOOS, goruntime.GOARCH))
+	if _, err := os.Stat(assets); err != nil && os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("the envtest binaries are not installed, run make test")
+	}
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: assets,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = testEnv.Stop() })
+
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appv1.AppSpec{
+			Image:          "nginx:1.27",
+			Port:           8080,
+			Ingress:        &appv1.IngressSpec{Host: "web.example.com"},
+			Service:        &appv1.ServiceSpec{Ports: []appv1.ServicePort{{Name: "http", Port: 80, TargetPort: 8080}}},
+			ServiceAccount: &appv1.ServiceAccountSpec{},
+		},
+	}
+	r := &Renderer{Scheme: scheme}
+	objs, err := r.Render(ctx, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	live := app.DeepCopy()
+	if err := c.Create(ctx, live); err != nil {
+		t.Fatal(err)
+	}
+	defaulted, err := applyDefaults(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(defaulted.Spec, live.Spec) {
+		t.Errorf("defaulted spec = %+v, want the spec stored by the API server %+v", defaulted.Spec, live.Spec)
+	}
+
+	rec := &controller.AppReconciler{Client: c, Scheme: scheme}
+	for range 3 {
+		if _, err := rec.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(app)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, obj := range objs {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), got); err != nil {
+			t.Errorf("Get(%s %s) = %v, want the reconciled object", obj.GetKind(), obj.GetName(), err)
+			continue
+		}
+		if path, ok := subset(obj.Object, got.Object, obj.GetKind()); !ok {
+			t.Errorf("rendered %s differs from the reconciled object", path)
+		}
+	}
+}
+
+// subset reports whether the fields of rendered have the same value in live,
+// or the path of the first field that has not. The defaults of the API server
+// and empty rendered fields are ignored.
+func subset(rendered, live interface{}, path string) (string, bool) {
+	switch r := rendered.(type) {
+	case map[string]interface{}:
+		l, _ := live.(map[string]interface{})
+		for k, v := range r {
+			if p, ok := subset(v, l[k], path+"."+k); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	case []interface{}:
+		l, _ := live.([]interface{})
+		if len(r) != len(l) {
+			return path, len(r) == 0
+		}
+		for i := range r {
+			if p, ok := subset(r[i], l[i], fmt.Sprintf("%s[%d]", path, i)); !ok {
+				return p, false
+			}
+		}
+		return "", true
+	case nil:
+		return "", true
+	default:
+		return path, equality.Semantic.DeepEqual(rendered, live)
+	}
+}
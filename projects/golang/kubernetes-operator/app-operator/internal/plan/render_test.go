@@ -0,0 +1,84 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/controller"
+)
+
+func TestRender(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := appv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: appv1.AppSpec{
+			Image:          "nginx:1.27",
+			Port:           8080,
+			Ingress:        &appv1.IngressSpec{Host: "web.example.com"},
+			TLS:            &appv1.TLSSpec{IssuerRef: appv1.IssuerReference{Name: "letsencrypt"}},
+			ServiceAccount: &appv1.ServiceAccountSpec{},
+		},
+	}
+	r := &Renderer{Scheme: scheme, APIs: controller.AvailableAPIs{CertManager: true}}
+	objs, err := r.Render(context.Background(), app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, obj := range objs {
+		kinds = append(kinds, obj.GetKind()+"/"+obj.GetName())
+		if obj.GetNamespace() != "shop" || len(obj.GetOwnerReferences()) != 0 || obj.Object["status"] != nil {
+			t.Errorf("%s %s = %v, want a manifest of the namespace shop", obj.GetKind(), obj.GetName(), obj.Object)
+		}
+	}
+	want := "ServiceAccount/web-sa,Deployment/web-app,Service/web-svc,Ingress/web-ingress,Certificate/web-tls"
+	if strings.Join(kinds, ",") != want {
+		t.Errorf("rendered %v, want %s", kinds, want)
+	}
+	containers, _, _ := unstructured.NestedSlice(objs[1].Object, "spec", "template", "spec", "containers")
+	if len(containers) != 1 || containers[0].(map[string]interface{})["image"] != "nginx:1.27" {
+		t.Errorf("Deployment containers = %v, want nginx:1.27", containers)
+	}
+	// The defaults of the CRD are applied
+	if automount, _, _ := unstructured.NestedBool(objs[1].Object, "spec", "template", "spec", "automountServiceAccountToken"); !automount {
+		t.Errorf("automountServiceAccountToken = %v, want the default of spec.serviceAccount", objs[1].Object)
+	}
+
+	// Without cert-manager, the Certificate is not rendered
+	r.APIs.CertManager = false
+	if objs, err = r.Render(context.Background(), app); err != nil {
+		t.Fatal(err)
+	}
+	if last := objs[len(objs)-1]; last.GetKind() != "Ingress" {
+		t.Errorf("last object = %s, want the Ingress", last.GetKind())
+	}
+}
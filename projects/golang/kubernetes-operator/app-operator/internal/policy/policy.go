@@ -0,0 +1,142 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy checks Apps against the rules set by the cluster
+// administrators, the equivalent of the Kyverno policies of the repository
+// applied to the pods the App would create.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+// Action is what happens to an App breaking the policy
+type Action string
+
+const (
+	// Enforce rejects the App in the webhook and stops its reconciliation
+	Enforce Action = "Enforce"
+	// Audit only reports the violations, as webhook warnings and in the PolicyViolation condition
+	Audit Action = "Audit"
+)
+
+// Policy is the set of rules every App must follow. The zero value allows everything.
+type Policy struct {
+	Action Action
+
+	// RequiredLabels must be set on the App, with a non-empty value.
+	// They are copied to the pods of the App.
+	RequiredLabels []string
+	// AllowedRegistries are the registries, or registry/repository prefixes, images are pulled from.
+	// Images without a registry are pulled from docker.io, e.g. nginx is docker.io/library/nginx.
+	AllowedRegistries []string
+	// DisallowPrivileged rejects privileged containers
+	DisallowPrivileged bool
+	// RequireNonRoot requires every container to set runAsNonRoot or a non-zero runAsUser
+	RequireNonRoot bool
+	// RequireLimits requires CPU and memory limits on every container
+	RequireLimits bool
+}
+
+// Enabled reports whether the policy has any rule
+func (p Policy) Enabled() bool {
+	return len(p.RequiredLabels) > 0 || len(p.AllowedRegistries) > 0 ||
+		p.DisallowPrivileged || p.RequireNonRoot || p.RequireLimits
+}
+
+// Check returns the violations of the policy by the App
+func (p Policy) Check(app *appv1.App) field.ErrorList {
+	var errs field.ErrorList
+
+	labels := field.NewPath("metadata", "labels")
+	for _, l := range p.RequiredLabels {
+		if app.Labels[l] == "" {
+			errs = append(errs, field.Required(labels.Key(l), "the label is required by the policy"))
+		}
+	}
+
+	spec := field.NewPath("spec")
+	app.Spec.VisitContainers(spec, func(c *corev1.Container, path *field.Path) {
+		errs = append(errs, p.checkContainer(c, path)...)
+	})
+	return errs
+}
+
+func (p Policy) checkContainer(c *corev1.Container, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(p.AllowedRegistries) > 0 && !allowedImage(c.Image, p.AllowedRegistries) {
+		errs = append(errs, field.Forbidden(path.Child("image"),
+			fmt.Sprintf("%s is not pulled from an allowed registry: %s", c.Image, strings.Join(p.AllowedRegistries, ", "))))
+	}
+
+	sc := c.SecurityContext
+	if sc == nil {
+		sc = &corev1.SecurityContext{}
+	}
+	if p.DisallowPrivileged && sc.Privileged != nil && *sc.Privileged {
+		errs = append(errs, field.Forbidden(path.Child("securityContext", "privileged"), "privileged containers are not allowed"))
+	}
+	if p.RequireNonRoot {
+		nonRoot := sc.RunAsNonRoot != nil && *sc.RunAsNonRoot
+		if sc.RunAsUser != nil {
+			nonRoot = *sc.RunAsUser != 0
+		}
+		if !nonRoot {
+			errs = append(errs, field.Required(path.Child("securityContext", "runAsNonRoot"),
+				"containers must run as a non-root user"))
+		}
+	}
+	if p.RequireLimits {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := c.Resources.Limits[name]; !ok {
+				errs = append(errs, field.Required(path.Child("resources", "limits").Key(string(name)),
+					"resource limits are required"))
+			}
+		}
+	}
+	return errs
+}
+
+// allowedImage reports whether the image is pulled from one of the registries
+func allowedImage(image string, registries []string) bool {
+	ref := normalizeImage(image)
+	for _, r := range registries {
+		r = strings.TrimSuffix(r, "/")
+		if strings.HasPrefix(ref, r+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImage prefixes an image with the registry it is pulled from,
+// following the rules of the container runtimes for Docker Hub images
+func normalizeImage(image string) string {
+	first, rest, found := strings.Cut(image, "/")
+	if !found {
+		return "docker.io/library/" + image
+	}
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return image
+	}
+	return "docker.io/" + first + "/" + rest
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+)
+
+func TestCheck(t *testing.T) {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	strict := Policy{
+		RequiredLabels:     []string{"test"},
+		AllowedRegistries:  []string{"docker.io/library", "registry.example.com/"},
+		DisallowPrivileged: true,
+		RequireNonRoot:     true,
+		RequireLimits:      true,
+	}
+	compliant := func() *appv1.App {
+		return &appv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"test": "web"}},
+			Spec: appv1.AppSpec{
+				Image:           "nginx:1.27",
+				SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)},
+				Resources:       &appv1.ResourcesSpec{Limits: limits},
+				Sidecars: []corev1.Container{{
+					Name:            "proxy",
+					Image:           "registry.example.com/proxy:1.0",
+					SecurityContext: &corev1.SecurityContext{RunAsUser: ptr.To[int64](1000)},
+					Resources:       corev1.ResourceRequirements{Limits: limits},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		mutate func(app *appv1.App)
+		want   []string
+	}{
+		{name: "no rules", mutate: func(app *appv1.App) { app.Spec = appv1.AppSpec{Image: "ghcr.io/x/y"} }},
+		{name: "compliant", policy: strict},
+		{
+			name:   "missing label",
+			policy: strict,
+			mutate: func(app *appv1.App) { app.Labels["test"] = "" },
+			want:   []string{"metadata.labels[test]"},
+		},
+		{
+			name:   "registries",
+			policy: strict,
+			mutate: func(app *appv1.App) {
+				app.Spec.Image = "bitnami/nginx"
+				app.Spec.Sidecars[0].Image = "registry.example.com.evil.io/proxy"
+			},
+			want: []string{"spec.image", "spec.sidecars[0].image"},
+		},
+		{
+			name:   "privileged and root",
+			policy: strict,
+			mutate: func(app *appv1.App) {
+				app.Spec.SecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true), RunAsNonRoot: ptr.To(true)}
+				app.Spec.Sidecars[0].SecurityContext.RunAsUser = ptr.To[int64](0)
+			},
+			want: []string{"spec.securityContext.privileged", "spec.sidecars[0].securityContext.runAsNonRoot"},
+		},
+		{
+			name:   "limits",
+			policy: strict,
+			mutate: func(app *appv1.App) {
+				app.Spec.Resources = nil
+				app.Spec.InitContainers = []corev1.Container{{
+					Name: "migrate", Image: "registry.example.com/migrate", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)},
+					Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")}},
+				}}
+			},
+			want: []string{"spec.resources.limits[cpu]", "spec.resources.limits[memory]", "spec.initContainers[0].resources.limits[cpu]"},
+		},
+		{
+			// The init container pulling the model runs the image of the app container
+			name:   "inference",
+			policy: strict,
+			mutate: func(app *appv1.App) {
+				app.Spec.Image = "ollama/ollama:0.5.7"
+				app.Spec.Inference = &appv1.InferenceSpec{Model: "llama3.2:1b"}
+			},
+			want: []string{"spec.image", "spec.inference.image"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := compliant()
+			if tt.mutate != nil {
+				tt.mutate(app)
+			}
+			var got []string
+			for _, err := range tt.policy.Check(app) {
+				got = append(got, err.Field)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	for image, want := range map[string]string{
+		"nginx":                       "docker.io/library/nginx",
+		"bitnami/nginx:1.27":          "docker.io/bitnami/nginx:1.27",
+		"ghcr.io/org/app@sha256:abcd": "ghcr.io/org/app@sha256:abcd",
+		"localhost/app":               "localhost/app",
+		"registry:5000/app":           "registry:5000/app",
+	} {
+		if got := normalizeImage(image); got != want {
+			t.Errorf("normalizeImage(%s) = %s, want %s", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secretstore reads the secrets referenced by AppSpec.SecretsFrom.
+package secretstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider reads secrets on behalf of the Apps of a namespace
+type Provider interface {
+	// Get returns the keys and values of the secret at p
+	Get(ctx context.Context, namespace, p string) (map[string][]byte, error)
+}
+
+// File is a file-backed stand-in for a vault. The secret at path p for the
+// Apps of a namespace is the JSON object of string values stored in
+// <Root>/<namespace>/<p>.json, e.g. a ConfigMap or a Secret mounted in the
+// operator. The Apps of a namespace cannot read the documents of another.
+type File struct {
+	Root string
+}
+
+// Get implements Provider
+func (f File) Get(_ context.Context, namespace, p string) (map[string][]byte, error) {
+	if namespace == "" || strings.ContainsAny(namespace, `/\`) || namespace == "." || namespace == ".." {
+		return nil, fmt.Errorf("invalid namespace %q", namespace)
+	}
+	// Cleaning from the namespace directory keeps ".." inside it
+	name := filepath.Join(f.Root, namespace, filepath.FromSlash(path.Clean("/"+p))+".json")
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", p, err)
+	}
+	data := make(map[string][]byte, len(values))
+	for k, v := range values {
+		data[k] = []byte(v)
+	}
+	return data, nil
+}
+
+// SharedWithAnnotation lists the namespaces allowed to read a Secret through
+// the Secret provider, separated by commas. "*" shares it with every namespace.
+const SharedWithAnnotation = "apps.test.local/shared-with"
+
+// Secret reads a Secret of another namespace, at path <namespace>/<name>.
+// The Secret must be shared with the namespace of the App through
+// SharedWithAnnotation, otherwise any App could read any Secret of the cluster.
+type Secret struct {
+	Reader client.Reader
+}
+
+// Get implements Provider
+func (s Secret) Get(ctx context.Context, namespace, p string) (map[string][]byte, error) {
+	ns, name, ok := strings.Cut(p, "/")
+	if !ok || ns == "" || name == "" {
+		return nil, fmt.Errorf("invalid path %q, expected <namespace>/<name>", p)
+	}
+	secret := &corev1.Secret{}
+	if err := s.Reader.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, secret); err != nil {
+		return nil, err
+	}
+	if ns != namespace && !sharedWith(secret, namespace) {
+		return nil, fmt.Errorf("secret %s is not shared with namespace %s", p, namespace)
+	}
+	return secret.Data, nil
+}
+
+func sharedWith(secret *corev1.Secret, namespace string) bool {
+	namespaces := strings.Split(secret.Annotations[SharedWithAnnotation], ",")
+	for i := range namespaces {
+		namespaces[i] = strings.TrimSpace(namespaces[i])
+	}
+	return slices.Contains(namespaces, "*") || slices.Contains(namespaces, namespace)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFile(t *testing.T) {
+	root := t.TempDir()
+	for ns, password := range map[string]string{"default": "s3cret", "shop": "sh0p"} {
+		if err := os.MkdirAll(filepath.Join(root, ns, "web"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, ns, "web", "db.json"), []byte(`{"password":"`+password+`"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f := File{Root: root}
+
+	data, err := f.Get(context.Background(), "default", "web/db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data["password"]); got != "s3cret" {
+		t.Errorf("password = %q, want s3cret", got)
+	}
+	// ".." cannot escape the directory of the namespace
+	if data, err := f.Get(context.Background(), "default", "../web/db"); err != nil || string(data["password"]) != "s3cret" {
+		t.Errorf("Get(../web/db) = %v, %v", data, err)
+	}
+	if _, err := f.Get(context.Background(), "default", "../shop/web/db"); err == nil {
+		t.Error("Get(../shop/web/db) read the document of the namespace shop")
+	}
+	for _, ns := range []string{"", "..", "shop/.."} {
+		if _, err := f.Get(context.Background(), ns, "default/web/db"); err == nil {
+			t.Errorf("Get(%q, default/web/db) succeeded", ns)
+		}
+	}
+	if _, err := f.Get(context.Background(), "default", "web/missing"); err == nil {
+		t.Error("Get(web/missing) succeeded")
+	}
+}
+
+func TestSecret(t *testing.T) {
+	secret := func(name, sharedWith string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shared"},
+			Data:       map[string][]byte{"token": []byte(name)},
+		}
+		if sharedWith != "" {
+			s.Annotations = map[string]string{SharedWithAnnotation: sharedWith}
+		}
+		return s
+	}
+	s := Secret{Reader: fake.NewClientBuilder().WithObjects(
+		secret("private", ""),
+		secret("team", "web, api"),
+		secret("public", "*"),
+	).Build()}
+
+	tests := []struct {
+		namespace string
+		path      string
+		wantErr   bool
+	}{
+		{namespace: "web", path: "shared/private", wantErr: true},
+		{namespace: "shared", path: "shared/private"},
+		{namespace: "api", path: "shared/team"},
+		{namespace: "batch", path: "shared/team", wantErr: true},
+		{namespace: "batch", path: "shared/public"},
+		{namespace: "web", path: "shared/missing", wantErr: true},
+		{namespace: "web", path: "public", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace+"/"+tt.path, func(t *testing.T) {
+			_, err := s.Get(context.Background(), tt.namespace, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
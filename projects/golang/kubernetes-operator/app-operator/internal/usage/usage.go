@@ -0,0 +1,201 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage samples the CPU and memory usage of pods from the
+// metrics.k8s.io API and keeps a rolling window of samples per App.
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// ContainerUsage is the usage of one container of a pod
+type ContainerUsage struct {
+	Pod       string
+	Container string
+	// CPU in millicores
+	CPU float64
+	// Memory in bytes
+	Memory float64
+}
+
+// Source returns the current usage of the containers of the pods matching selector
+type Source interface {
+	ContainerUsage(ctx context.Context, namespace string, selector labels.Selector) ([]ContainerUsage, error)
+}
+
+// podMetricsList is the subset of metrics.k8s.io/v1beta1 PodMetricsList read by MetricsAPI
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Containers []struct {
+			Name  string              `json:"name"`
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// MetricsAPI reads the PodMetrics served by metrics-server. The REST client
+// avoids depending on the k8s.io/metrics module for a single request.
+type MetricsAPI struct {
+	client rest.Interface
+}
+
+// NewMetricsAPI returns a Source reading the metrics.k8s.io/v1beta1 API of the cluster of cfg
+func NewMetricsAPI(cfg *rest.Config) (*MetricsAPI, error) {
+	cfg = rest.CopyConfig(cfg)
+	cfg.GroupVersion = &schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	cfg.APIPath = "/apis"
+	cfg.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	client, err := rest.RESTClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsAPI{client: client}, nil
+}
+
+// ContainerUsage implements Source
+func (m *MetricsAPI) ContainerUsage(ctx context.Context, namespace string, selector labels.Selector) ([]ContainerUsage, error) {
+	raw, err := m.client.Get().
+		Namespace(namespace).
+		Resource("pods").
+		Param("labelSelector", selector.String()).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	list := podMetricsList{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("decoding PodMetricsList: %w", err)
+	}
+
+	var usages []ContainerUsage
+	for _, pod := range list.Items {
+		for _, c := range pod.Containers {
+			usages = append(usages, ContainerUsage{
+				Pod:       pod.Metadata.Name,
+				Container: c.Name,
+				CPU:       float64(c.Usage.Cpu().MilliValue()),
+				Memory:    float64(c.Usage.Memory().Value()),
+			})
+		}
+	}
+	return usages, nil
+}
+
+// Sample is the usage of the app container of every pod of an App at a point in time
+type Sample struct {
+	Time time.Time
+	// CPU of each pod in millicores
+	CPU []float64
+	// Memory of each pod in bytes
+	Memory []float64
+}
+
+// Recorder keeps the samples of each App taken over the last Window.
+// Samples live in memory and are lost when the operator restarts.
+type Recorder struct {
+	Window time.Duration
+
+	mu      sync.Mutex
+	samples map[types.NamespacedName][]Sample
+}
+
+// NewRecorder returns a Recorder keeping the samples of the last window
+func NewRecorder(window time.Duration) *Recorder {
+	return &Recorder{Window: window, samples: map[types.NamespacedName][]Sample{}}
+}
+
+// Add records a sample of an App and drops the samples out of the window.
+// Samples are added in chronological order.
+func (r *Recorder) Add(key types.NamespacedName, s Sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[key] = append(r.samples[key], s)
+	r.expire(key, s.Time)
+}
+
+// Samples returns the samples of an App within the window ending at now
+func (r *Recorder) Samples(key types.NamespacedName, now time.Time) []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expire(key, now)
+	return append([]Sample(nil), r.samples[key]...)
+}
+
+// Last returns the time of the last sample of an App, zero without samples
+func (r *Recorder) Last(key types.NamespacedName) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	samples := r.samples[key]
+	if len(samples) == 0 {
+		return time.Time{}
+	}
+	return samples[len(samples)-1].Time
+}
+
+// Forget drops the samples of a deleted App
+func (r *Recorder) Forget(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.samples, key)
+}
+
+func (r *Recorder) expire(key types.NamespacedName, now time.Time) {
+	samples := r.samples[key]
+	i := 0
+	for i < len(samples) && now.Sub(samples[i].Time) > r.Window {
+		i++
+	}
+	if i == len(samples) {
+		delete(r.samples, key)
+		return
+	}
+	r.samples[key] = samples[i:]
+}
+
+// Percentile returns the nearest-rank p-th percentile (0 < p <= 100) of values
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+// newFakeMetricsServer serves the PodMetricsList of the namespace "default"
+func newFakeMetricsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("labelSelector"); got != "app=web" {
+			t.Errorf("labelSelector = %q, want app=web", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+  "kind": "PodMetricsList",
+  "apiVersion": "metrics.k8s.io/v1beta1",
+  "items": [
+    {"metadata": {"name": "web-1"}, "containers": [
+      {"name": "app", "usage": {"cpu": "120m", "memory": "64Mi"}},
+      {"name": "proxy", "usage": {"cpu": "5m", "memory": "16Mi"}}
+    ]},
+    {"metadata": {"name": "web-2"}, "containers": [
+      {"name": "app", "usage": {"cpu": "1500000n", "memory": "1Gi"}}
+    ]}
+  ]
+}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMetricsAPI(t *testing.T) {
+	srv := newFakeMetricsServer(t)
+	m, err := NewMetricsAPI(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := m.ContainerUsage(context.Background(), "default", labels.SelectorFromSet(labels.Set{"app": "web"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ContainerUsage{
+		{Pod: "web-1", Container: "app", CPU: 120, Memory: 64 << 20},
+		{Pod: "web-1", Container: "proxy", CPU: 5, Memory: 16 << 20},
+		{Pod: "web-2", Container: "app", CPU: 2, Memory: 1 << 30},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("usage mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := m.ContainerUsage(context.Background(), "other", labels.Everything()); err == nil {
+		t.Error("ContainerUsage() succeeded on a missing namespace")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "web"}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecorder(time.Hour)
+
+	if !r.Last(key).IsZero() {
+		t.Error("Last() is set without samples")
+	}
+	for i := 0; i < 4; i++ {
+		r.Add(key, Sample{Time: start.Add(time.Duration(i) * 30 * time.Minute), CPU: []float64{float64(i)}})
+	}
+	// The samples at 0 and 30m are out of the window ending at 1h30m
+	if got := len(r.Samples(key, start.Add(90*time.Minute))); got != 3 {
+		t.Errorf("len(Samples()) = %d, want 3", got)
+	}
+	if got := r.Last(key); !got.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("Last() = %s", got)
+	}
+	if got := len(r.Samples(key, start.Add(3*time.Hour))); got != 0 {
+		t.Errorf("len(Samples()) = %d after the window, want 0", got)
+	}
+
+	r.Add(key, Sample{Time: start})
+	r.Forget(key)
+	if got := len(r.Samples(key, start)); got != 0 {
+		t.Errorf("len(Samples()) = %d after Forget(), want 0", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5}
+	for _, tt := range []struct {
+		p    float64
+		want float64
+	}{{50, 5}, {95, 10}, {10, 1}, {100, 10}} {
+		if got := Percentile(values, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1 holds the admission webhooks of the apps.test.local/v1 API
+package v1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/policy"
+)
+
+// SetupAppWebhookWithManager registers the validating webhook of App
+func SetupAppWebhookWithManager(mgr ctrl.Manager, p policy.Policy) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&appv1.App{}).
+		WithValidator(&AppCustomValidator{Policy: p, Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// +kubebuilder:webhook:path=/validate-apps-test-local-v1-app,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.test.local,resources=apps,verbs=create;update,versions=v1,name=vapp-v1.kb.io,admissionReviewVersions=v1
+
+// AppCustomValidator rejects the Apps the reconciler would refuse: invalid
+// specs, and violations of the policy when it is enforced. It also rejects
+// the rules of spec.serviceAccount the requesting user does not hold, so that
+// the Role of the App grants no more than its author could grant directly.
+type AppCustomValidator struct {
+	Policy policy.Policy
+	// Client creates the SubjectAccessReviews of the rules of spec.serviceAccount
+	Client client.Client
+}
+
+var _ admission.CustomValidator = &AppCustomValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *AppCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	app, ok := obj.(*appv1.App)
+	if !ok {
+		return nil, fmt.Errorf("expected an App, got %T", obj)
+	}
+	return v.validate(ctx, nil, app)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *AppCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	app, ok := newObj.(*appv1.App)
+	if !ok {
+		return nil, fmt.Errorf("expected an App, got %T", newObj)
+	}
+	old, ok := oldObj.(*appv1.App)
+	if !ok {
+		return nil, fmt.Errorf("expected an App, got %T", oldObj)
+	}
+	return v.validate(ctx, old, app)
+}
+
+// ValidateDelete implements admission.CustomValidator. Deletions are always allowed.
+func (v *AppCustomValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks app, old being the App it replaces or nil on creation
+func (v *AppCustomValidator) validate(ctx context.Context, old, app *appv1.App) (admission.Warnings, error) {
+	errs := app.Spec.Validate(field.NewPath("spec"))
+	if len(errs) == 0 {
+		reviewErrs, err := v.reviewRules(ctx, old, app)
+		if err != nil {
+			return nil, apierrors.NewInternalError(err)
+		}
+		errs = append(errs, reviewErrs...)
+	}
+
+	var warnings admission.Warnings
+	violations := v.Policy.Check(app)
+	if v.Policy.Action == policy.Audit {
+		for _, err := range violations {
+			warnings = append(warnings, "policy violation: "+err.Error())
+		}
+	} else {
+		errs = append(errs, violations...)
+	}
+
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(appv1.GroupVersion.WithKind("App").GroupKind(), app.Name, errs)
+	}
+	return warnings, nil
+}
+
+// reviewRules returns the rules of spec.serviceAccount the requesting user is
+// not allowed to use in the namespace of the App. The rules of old were
+// reviewed when they were added: other users may edit the App, e.g. to
+// change its image, without holding them.
+func (v *AppCustomValidator) reviewRules(ctx context.Context, old, app *appv1.App) (field.ErrorList, error) {
+	if app.Spec.ServiceAccount == nil || len(app.Spec.ServiceAccount.Rules) == 0 {
+		return nil, nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var reviewed []rbacv1.PolicyRule
+	if old != nil && old.Spec.ServiceAccount != nil {
+		reviewed = old.Spec.ServiceAccount.Rules
+	}
+
+	var errs field.ErrorList
+	path := field.NewPath("spec", "serviceAccount", "rules")
+	for i, rule := range app.Spec.ServiceAccount.Rules {
+		if containsRule(reviewed, rule) {
+			continue
+		}
+		for _, attrs := range ruleAttributes(app.Namespace, rule) {
+			sar := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: attrs,
+				User:               req.UserInfo.Username,
+				Groups:             req.UserInfo.Groups,
+				UID:                req.UserInfo.UID,
+				Extra:              extra(req.UserInfo.Extra),
+			}}
+			if err := v.Client.Create(ctx, sar); err != nil {
+				return nil, err
+			}
+			if !sar.Status.Allowed {
+				errs = append(errs, field.Forbidden(path.Index(i), fmt.Sprintf(
+					"user %q cannot %s %s in namespace %s, the App cannot grant it",
+					req.UserInfo.Username, attrs.Verb, resourceString(attrs), app.Namespace)))
+				break
+			}
+		}
+	}
+	return errs, nil
+}
+
+// containsRule reports whether rules holds rule
+func containsRule(rules []rbacv1.PolicyRule, rule rbacv1.PolicyRule) bool {
+	for _, r := range rules {
+		if reflect.DeepEqual(r, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleAttributes returns the requests a rule allows in a namespace, one per
+// verb, API group, resource and resource name. Wildcards are reviewed as
+// they are: only a user holding the wildcard is allowed.
+func ruleAttributes(namespace string, rule rbacv1.PolicyRule) []*authorizationv1.ResourceAttributes {
+	names := rule.ResourceNames
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	var attrs []*authorizationv1.ResourceAttributes
+	for _, verb := range rule.Verbs {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				resource, subresource, _ := strings.Cut(resource, "/")
+				for _, name := range names {
+					attrs = append(attrs, &authorizationv1.ResourceAttributes{
+						Namespace:   namespace,
+						Verb:        verb,
+						Group:       group,
+						Resource:    resource,
+						Subresource: subresource,
+						Name:        name,
+					})
+				}
+			}
+		}
+	}
+	return attrs
+}
+
+// resourceString formats the resource of a request, e.g. apps/deployments/scale
+func resourceString(attrs *authorizationv1.ResourceAttributes) string {
+	s := attrs.Resource
+	if attrs.Group != "" {
+		s = attrs.Group + "/" + s
+	}
+	if attrs.Subresource != "" {
+		s += "/" + attrs.Subresource
+	}
+	if attrs.Name != "" {
+		s += " " + attrs.Name
+	}
+	return s
+}
+
+// extra converts the extra attributes of the requesting user
+func extra(in map[string]authenticationv1.ExtraValue) map[string]authorizationv1.ExtraValue {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]authorizationv1.ExtraValue, len(in))
+	for k, v := range in {
+		out[k] = authorizationv1.ExtraValue(v)
+	}
+	return out
+}
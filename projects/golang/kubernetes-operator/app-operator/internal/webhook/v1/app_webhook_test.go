@@ -0,0 +1,142 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appv1 "github.com/balleon/app-operator/api/v1"
+	"github.com/balleon/app-operator/internal/policy"
+)
+
+func TestAppCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appv1.AppSpec{Image: "nginx:1.27", Port: 8080},
+	}
+	labels := policy.Policy{Action: policy.Enforce, RequiredLabels: []string{"test"}}
+
+	if _, err := (&AppCustomValidator{}).ValidateCreate(ctx, app); err != nil {
+		t.Errorf("ValidateCreate() = %v without policy", err)
+	}
+
+	_, err := (&AppCustomValidator{Policy: labels}).ValidateCreate(ctx, app)
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "metadata.labels[test]") {
+		t.Errorf("ValidateCreate() = %v, want the missing label", err)
+	}
+
+	labels.Action = policy.Audit
+	warnings, err := (&AppCustomValidator{Policy: labels}).ValidateUpdate(ctx, app, app)
+	if err != nil || len(warnings) != 1 {
+		t.Errorf("ValidateUpdate() = %v, %v, want a warning in audit mode", warnings, err)
+	}
+
+	// The checks of AppSpec.Validate apply whatever the policy
+	invalid := app.DeepCopy()
+	invalid.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}
+	_, err = (&AppCustomValidator{Policy: labels}).ValidateUpdate(ctx, app, invalid)
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "spec.volumeMounts[0].name") {
+		t.Errorf("ValidateUpdate() = %v, want the undeclared volume", err)
+	}
+
+	if _, err := (&AppCustomValidator{Policy: labels}).ValidateDelete(ctx, invalid); err != nil {
+		t.Errorf("ValidateDelete() = %v", err)
+	}
+}
+
+func TestAppCustomValidatorServiceAccountRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	// The user may read ConfigMaps in the namespace default, and nothing else
+	var reviews []authorizationv1.ResourceAttributes
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			sar := obj.(*authorizationv1.SubjectAccessReview)
+			attrs := *sar.Spec.ResourceAttributes
+			reviews = append(reviews, attrs)
+			sar.Status.Allowed = sar.Spec.User == "alice" && attrs.Namespace == "default" &&
+				attrs.Group == "" && attrs.Resource == "configmaps" && (attrs.Verb == "get" || attrs.Verb == "list")
+			return nil
+		},
+	}).Build()
+	v := &AppCustomValidator{Client: c}
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "alice"}},
+	})
+
+	app := &appv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appv1.AppSpec{
+			Image: "nginx:1.27",
+			Port:  8080,
+			ServiceAccount: &appv1.ServiceAccountSpec{Rules: []rbacv1.PolicyRule{{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"get", "list"},
+			}}},
+		},
+	}
+	if _, err := v.ValidateCreate(ctx, app); err != nil {
+		t.Errorf("ValidateCreate() = %v, want the rules held by the user allowed", err)
+	}
+	if len(reviews) != 2 {
+		t.Errorf("reviews = %+v, want one per verb", reviews)
+	}
+
+	broad := app.DeepCopy()
+	broad.Spec.ServiceAccount.Rules = append(broad.Spec.ServiceAccount.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{"*"},
+		Resources: []string{"*"},
+		Verbs:     []string{"*"},
+	})
+	_, err := v.ValidateCreate(ctx, broad)
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "spec.serviceAccount.rules[1]") {
+		t.Errorf("ValidateCreate() = %v, want the over-broad rule rejected", err)
+	}
+	if _, err := v.ValidateUpdate(ctx, app, broad); !apierrors.IsInvalid(err) {
+		t.Errorf("ValidateUpdate() = %v, want the added rule rejected", err)
+	}
+
+	// Rules already in the App are not reviewed again, e.g. when bob changes the image
+	bobCtx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "bob"}},
+	})
+	image := app.DeepCopy()
+	image.Spec.Image = "nginx:1.28"
+	if _, err := v.ValidateUpdate(bobCtx, app, image); err != nil {
+		t.Errorf("ValidateUpdate() = %v, want the reviewed rules kept", err)
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// ollama-stub stands in for the ollama binary to test inference Apps without
+// downloading models. It implements the commands used by the operator,
+// serve, list, pull and run, and the API they call, plus the OpenAI-compatible
+// /v1/models and /v1/chat/completions endpoints with a canned answer.
+//
+// Pulled models are empty files in OLLAMA_MODELS, so that the init container
+// and the app container share them. OLLAMA_STUB_LOAD_DELAY delays the first
+// run of a model, to observe the pods waiting for the model to be loaded.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: ollama serve|list|pull MODEL|run MODEL [PROMPT]")
+	}
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		host = "127.0.0.1:11434"
+	}
+	switch args[0] {
+	case "serve":
+		delay, _ := time.ParseDuration(os.Getenv("OLLAMA_STUB_LOAD_DELAY"))
+		s := &server{dir: modelsDir(), loadDelay: delay, loaded: map[string]bool{}}
+		fmt.Println("Listening on", host)
+		return http.ListenAndServe(host, s.handler())
+	case "list":
+		var tags struct {
+			Models []struct {
+				Name string `json:"name"`
+			} `json:"models"`
+		}
+		if err := call(host, http.MethodGet, "/api/tags", nil, &tags); err != nil {
+			return err
+		}
+		fmt.Println("NAME")
+		for _, m := range tags.Models {
+			fmt.Println(m.Name)
+		}
+		return nil
+	case "pull", "run":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: ollama %s MODEL", args[0])
+		}
+		if args[0] == "pull" {
+			return call(host, http.MethodPost, "/api/pull", map[string]string{"model": args[1]}, nil)
+		}
+		prompt := strings.Join(args[2:], " ")
+		return call(host, http.MethodPost, "/api/generate", map[string]string{"model": args[1], "prompt": prompt}, nil)
+	}
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// modelsDir returns the directory of the pulled models
+func modelsDir() string {
+	if dir := os.Getenv("OLLAMA_MODELS"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "ollama-stub")
+}
+
+// call sends a JSON request to the server and decodes the JSON response into out
+func call(host, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	req, err := http.NewRequest(method, host+path, body)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("%s %s: %s %s", method, path, resp.Status, e.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// server is the stub of the Ollama API
+type server struct {
+	dir       string
+	loadDelay time.Duration
+
+	mu     sync.Mutex
+	loaded map[string]bool
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Ollama is running")
+	})
+	mux.HandleFunc("GET /api/tags", func(w http.ResponseWriter, r *http.Request) {
+		models := []map[string]string{}
+		for _, name := range s.models() {
+			models = append(models, map[string]string{"name": name, "model": name})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"models": models})
+	})
+	mux.HandleFunc("POST /api/pull", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "model is required"})
+			return
+		}
+		if err := s.pull(req.Model); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+	})
+	mux.HandleFunc("POST /api/generate", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if !s.load(req.Model) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("model %q not found, try pulling it first", req.Model)})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"model": req.Model, "response": "", "done": true})
+	})
+	mux.HandleFunc("GET /api/ps", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		models := []map[string]string{}
+		for name := range s.loaded {
+			models = append(models, map[string]string{"name": name, "model": name})
+		}
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"models": models})
+	})
+	mux.HandleFunc("GET /v1/models", func(w http.ResponseWriter, r *http.Request) {
+		data := []map[string]string{}
+		for _, name := range s.models() {
+			data = append(data, map[string]string{"id": name, "object": "model", "owned_by": "library"})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"object": "list", "data": data})
+	})
+	mux.HandleFunc("POST /v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if !s.load(req.Model) {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{
+				"message": fmt.Sprintf("model %q not found, try pulling it first", req.Model),
+				"type":    "api_error",
+			}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":      "chatcmpl-stub",
+			"object":  "chat.completion",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"message":       map[string]string{"role": "assistant", "content": "This is a stub answer."},
+				"finish_reason": "stop",
+			}},
+		})
+	})
+	return mux
+}
+
+// models returns the names of the pulled models
+func (s *server) models() []string {
+	entries, _ := os.ReadDir(s.dir)
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.ReplaceAll(e.Name(), "@", "/"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pull stores an empty model
+func (s *server) pull(model string) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(model), nil, 0o644)
+}
+
+// load loads a pulled model, waiting for the load delay the first time,
+// and reports whether the model is pulled
+func (s *server) load(model string) bool {
+	if model == "" {
+		return false
+	}
+	if _, err := os.Stat(s.path(model)); err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded[model] {
+		time.Sleep(s.loadDelay)
+		s.loaded[model] = true
+	}
+	return true
+}
+
+// path returns the file of a model
+func (s *server) path(model string) string {
+	return filepath.Join(s.dir, strings.ReplaceAll(model, "/", "@"))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
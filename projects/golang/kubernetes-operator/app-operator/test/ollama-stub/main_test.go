@@ -0,0 +1,70 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStub(t *testing.T) {
+	s := &server{dir: t.TempDir(), loaded: map[string]bool{}}
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+	t.Setenv("OLLAMA_HOST", ts.URL)
+
+	// The readiness probe fails until the model is pulled
+	if err := run([]string{"run", "llama3.2", ""}); err == nil {
+		t.Error("run before pull succeeded")
+	}
+	if err := run([]string{"pull", "llama3.2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"list"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"run", "llama3.2", ""}); err != nil {
+		t.Errorf("run after pull = %v", err)
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := call(ts.URL, http.MethodGet, "/v1/models", nil, &models); err != nil {
+		t.Fatal(err)
+	}
+	if len(models.Data) != 1 || models.Data[0].ID != "llama3.2" {
+		t.Errorf("/v1/models = %+v, want llama3.2", models.Data)
+	}
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	req := map[string]interface{}{"model": "llama3.2", "messages": []map[string]string{{"role": "user", "content": "Hi"}}}
+	if err := call(ts.URL, http.MethodPost, "/v1/chat/completions", req, &completion); err != nil {
+		t.Fatal(err)
+	}
+	if len(completion.Choices) != 1 || completion.Choices[0].Message.Content == "" {
+		t.Errorf("/v1/chat/completions = %+v, want one answer", completion)
+	}
+}